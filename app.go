@@ -2,12 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	goruntime "runtime"
+	"strings"
 
 	"dadjoke/pdf"
 
@@ -103,7 +104,7 @@ func (a *App) SaveFile(tempPath string, suggestedName string) (string, error) {
 	}
 
 	// Copy temp file to save location
-	if err := copyFile(tempPath, savePath); err != nil {
+	if err := pdf.CopyFile(tempPath, savePath); err != nil {
 		return "", fmt.Errorf("failed to save file: %w", err)
 	}
 
@@ -113,6 +114,43 @@ func (a *App) SaveFile(tempPath string, suggestedName string) (string, error) {
 	return savePath, nil
 }
 
+// SaveFilesToFolder opens a folder picker and copies each temp file into it.
+// Files are named after each document's Name; existing files are not overwritten.
+func (a *App) SaveFilesToFolder(documents []pdf.PDFDocument) ([]string, error) {
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Choose Folder",
+		CanCreateDirectories: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return nil, nil // User cancelled
+	}
+
+	var savedPaths []string
+	for _, doc := range documents {
+		// Names come from the frontend; keep them inside the chosen folder
+		name := pdf.SanitizeFileName(filepath.Base(doc.Name))
+		if name == "" {
+			name = "document"
+		}
+		if filepath.Ext(name) != ".pdf" {
+			name += ".pdf"
+		}
+
+		savePath := uniquePath(filepath.Join(dir, name))
+		if err := pdf.CopyFile(doc.Path, savePath); err != nil {
+			return savedPaths, fmt.Errorf("failed to save %s: %w", name, err)
+		}
+
+		pdf.CleanupTempFiles(doc.Path)
+		savedPaths = append(savedPaths, savePath)
+	}
+
+	return savedPaths, nil
+}
+
 // OpenFile opens a file with the system's default application
 func (a *App) OpenFile(path string) error {
 	var cmd *exec.Cmd
@@ -143,6 +181,16 @@ func (a *App) ValidatePDF(path string) error {
 	return pdf.ValidatePDF(path)
 }
 
+// UnlockPDF decrypts a password-protected PDF and returns the path of the unlocked copy
+func (a *App) UnlockPDF(path string, password string) (string, error) {
+	return pdf.UnlockPDF(path, password)
+}
+
+// EncryptPDF password-protects a PDF and returns the path of the encrypted copy
+func (a *App) EncryptPDF(path string, options pdf.EncryptOptions) (string, error) {
+	return pdf.EncryptPDF(a.ctx, path, options)
+}
+
 // ============================================================================
 // Compress Methods
 // ============================================================================
@@ -152,6 +200,41 @@ func (a *App) CompressPDF(path string, preset string) (*pdf.CompressionResult, e
 	return pdf.CompressPDF(a.ctx, path, pdf.CompressionPreset(preset))
 }
 
+// CompressPDFWithOptions compresses a PDF with a custom compression profile
+func (a *App) CompressPDFWithOptions(path string, options pdf.CompressionOptions) (*pdf.CompressionResult, error) {
+	return pdf.CompressPDFWithOptions(a.ctx, path, options)
+}
+
+// GetPresetOptions returns the compression options behind a named preset
+func (a *App) GetPresetOptions(preset string) (pdf.CompressionOptions, error) {
+	return pdf.OptionsForPreset(pdf.CompressionPreset(preset))
+}
+
+// CompressToTarget compresses a PDF to at most targetBytes, keeping as much quality as possible
+func (a *App) CompressToTarget(path string, targetBytes int64) (*pdf.CompressionResult, error) {
+	return pdf.CompressToTarget(a.ctx, path, targetBytes)
+}
+
+// CompressAllPresets compresses a PDF with every preset and returns the results ranked by size
+func (a *App) CompressAllPresets(path string) ([]pdf.CompressionResult, error) {
+	return pdf.CompressAllPresets(a.ctx, path)
+}
+
+// AnalyzePDF reports what is taking up space in a PDF and which preset would help most
+func (a *App) AnalyzePDF(path string) (*pdf.PDFAnalysis, error) {
+	return pdf.AnalyzePDF(a.ctx, path)
+}
+
+// PreviewCompression renders one page before and after compressing with a preset
+func (a *App) PreviewCompression(path string, preset string, pageIndex int) (*pdf.CompressionPreview, error) {
+	return pdf.PreviewCompression(a.ctx, path, pdf.CompressionPreset(preset), pageIndex)
+}
+
+// CompressBatch compresses many PDFs with one preset, several at a time
+func (a *App) CompressBatch(paths []string, preset string, concurrency int) (*pdf.BatchCompressionResult, error) {
+	return pdf.CompressBatch(a.ctx, paths, pdf.CompressionPreset(preset), concurrency)
+}
+
 // ============================================================================
 // Combine Methods
 // ============================================================================
@@ -166,11 +249,117 @@ func (a *App) MergeTwoFiles(pathA, pathB string, mode string) (*pdf.PDFDocument,
 	return pdf.MergeTwoFiles(a.ctx, pathA, pathB, pdf.MergeMode(mode))
 }
 
+// MergeDuplexScan assembles a double-sided document from front and reversed back scans
+func (a *App) MergeDuplexScan(frontsPath, backsPath string, blankBacks bool) (*pdf.PDFDocument, error) {
+	return pdf.MergeDuplexScan(a.ctx, frontsPath, backsPath, blankBacks)
+}
+
+// MergePattern collates pages from several PDFs according to a pattern such as "A1 B1 A2" or "A:2,B:1"
+func (a *App) MergePattern(documents []pdf.PDFDocument, pattern string) (*pdf.PDFDocument, error) {
+	return pdf.MergePattern(a.ctx, documents, pattern)
+}
+
+// InsertPDF inserts pages of one PDF into another after the given page (0 for the front)
+func (a *App) InsertPDF(targetPath, sourcePath string, afterPage int, sourcePages []int) (*pdf.PDFDocument, error) {
+	return pdf.InsertPDF(a.ctx, targetPath, sourcePath, afterPage, sourcePages)
+}
+
 // ReorderPages creates a new PDF with pages in the specified order
 func (a *App) ReorderPages(path string, pageOrder []int) (*pdf.PDFDocument, error) {
 	return pdf.ReorderPages(a.ctx, path, pageOrder)
 }
 
+// DeletePages creates a new PDF without the specified pages (1-indexed)
+func (a *App) DeletePages(path string, pages []int) (*pdf.PDFDocument, error) {
+	return pdf.DeletePages(a.ctx, path, pages)
+}
+
+// DuplicatePages creates a new PDF with a copy after each specified page (1-indexed)
+func (a *App) DuplicatePages(path string, pages []int) (*pdf.PDFDocument, error) {
+	return pdf.DuplicatePages(a.ctx, path, pages)
+}
+
+// ReversePages creates a new PDF with the page order reversed
+func (a *App) ReversePages(path string) (*pdf.PDFDocument, error) {
+	return pdf.ReversePages(a.ctx, path)
+}
+
+// ExtractOdd creates a new PDF with only the odd pages
+func (a *App) ExtractOdd(path string) (*pdf.PDFDocument, error) {
+	return pdf.ExtractOdd(a.ctx, path)
+}
+
+// ExtractEven creates a new PDF with only the even pages
+func (a *App) ExtractEven(path string) (*pdf.PDFDocument, error) {
+	return pdf.ExtractEven(a.ctx, path)
+}
+
+// ParsePageSelection validates a page selection expression such as "1-3,!2,l" against a page count.
+// Problems are reported in the result, with their position, rather than as an error.
+func (a *App) ParsePageSelection(expr string, pageCount int) pdf.PageSelectionResult {
+	pages, err := pdf.ParsePageSelection(expr, pageCount)
+	if err == nil {
+		return pdf.PageSelectionResult{Valid: true, Pages: pages}
+	}
+
+	result := pdf.PageSelectionResult{Error: err.Error()}
+	var selErr *pdf.PageSelectionError
+	if errors.As(err, &selErr) {
+		result.Error = selErr.Msg
+		result.Offset = selErr.Offset
+		result.Length = selErr.Length
+	}
+	return result
+}
+
+// InsertBlankPages creates a new PDF with blank pages after the given positions (0 for the front)
+func (a *App) InsertBlankPages(path string, positions []int, options pdf.BlankPageOptions) (*pdf.PDFDocument, error) {
+	return pdf.InsertBlankPages(a.ctx, path, positions, options)
+}
+
+// ============================================================================
+// Split Methods
+// ============================================================================
+
+// ExtractPages creates a new PDF with only the specified pages (1-indexed)
+func (a *App) ExtractPages(path string, pages []int) (*pdf.SplitResult, error) {
+	return pdf.ExtractPages(a.ctx, path, pages)
+}
+
+// SplitByRanges creates one PDF per range expression (e.g. "1-3", "9-")
+func (a *App) SplitByRanges(path string, ranges []string) ([]pdf.PDFDocument, error) {
+	return pdf.SplitByRanges(a.ctx, path, ranges)
+}
+
+// SplitEveryN creates one PDF per n consecutive pages
+func (a *App) SplitEveryN(path string, n int) ([]pdf.PDFDocument, error) {
+	return pdf.SplitEveryN(a.ctx, path, n)
+}
+
+// SplitBySize creates PDFs of consecutive pages that each stay under maxBytes
+func (a *App) SplitBySize(path string, maxBytes int64) ([]pdf.PDFDocument, error) {
+	return pdf.SplitBySize(a.ctx, path, maxBytes)
+}
+
+// SplitByBookmarks creates one PDF per bookmark at the given outline level (1 = top level)
+func (a *App) SplitByBookmarks(path string, level int) ([]pdf.PDFDocument, error) {
+	return pdf.SplitByBookmarks(a.ctx, path, level)
+}
+
+// ============================================================================
+// Rotate Methods
+// ============================================================================
+
+// ApplyRotations creates a new PDF with the given per-page rotations applied
+func (a *App) ApplyRotations(path string, rotations []pdf.PageRotation) (*pdf.RotateResult, error) {
+	return pdf.ApplyRotations(a.ctx, path, rotations)
+}
+
+// DetectOrientation suggests a rotation for every page, 0° for pages that are already upright
+func (a *App) DetectOrientation(path string) ([]pdf.OrientationSuggestion, error) {
+	return pdf.DetectOrientation(a.ctx, path)
+}
+
 // ============================================================================
 // Thumbnail Methods
 // ============================================================================
@@ -185,23 +374,27 @@ func (a *App) GenerateThumbnail(path string, pageIndex, width, height int) (*pdf
 	return pdf.GenerateThumbnail(a.ctx, path, pageIndex, width, height)
 }
 
+// GenerateRotatedThumbnail previews a page with a rotation applied
+func (a *App) GenerateRotatedThumbnail(path string, pageIndex, width, height int, rotation pdf.RotationAngle) (*pdf.ThumbnailResult, error) {
+	return pdf.GenerateRotatedThumbnail(a.ctx, path, pageIndex, width, height, rotation)
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
 
-func copyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return err
+// uniquePath appends " (2)", " (3)", ... to the file name until it doesn't exist
+func uniquePath(path string) string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
 	}
-	defer sourceFile.Close()
 
-	destFile, err := os.Create(dst)
-	if err != nil {
-		return err
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
 	}
-	defer destFile.Close()
-
-	_, err = io.Copy(destFile, sourceFile)
-	return err
 }
@@ -0,0 +1,412 @@
+package pdf
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Size categories reported by AnalyzePDF
+const (
+	CategoryImages      = "images"
+	CategoryFonts       = "fonts"
+	CategoryContent     = "content"
+	CategoryAttachments = "attachments"
+	CategoryMetadata    = "metadata"
+	CategoryOther       = "other"
+)
+
+// largestObjectsCount is how many objects PDFAnalysis.LargestObjects lists
+const largestObjectsCount = 10
+
+// maxFormDepth bounds the search for images inside nested form XObjects
+const maxFormDepth = 8
+
+// AnalyzePDF reports what is taking up space in a PDF: bytes by category, every image and
+// embedded font, the largest objects, and the compression preset most likely to help.
+// Sizes are the stored (compressed) stream lengths; "other" is everything else in the file,
+// such as page structure, annotations and the cross-reference table.
+func AnalyzePDF(ctx context.Context, path string) (*PDFAnalysis, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+
+	// Read without optimizing so duplicate images and fonts still count
+	pdfCtx, err := api.ReadContextFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+
+	a := &analyzer{
+		pdfCtx:  pdfCtx,
+		content: make(map[int]bool),
+		images:  make(map[int]*ImageAnalysis),
+		visited: make(map[int]bool),
+	}
+	if err := a.walkPages(); err != nil {
+		return nil, fmt.Errorf("cannot read pages: %w", err)
+	}
+	fonts := a.fontPrograms()
+
+	analysis := &PDFAnalysis{
+		FileSize:  info.Size(),
+		PageCount: pdfCtx.PageCount,
+	}
+	categories := map[string]*SizeCategory{}
+	for _, name := range []string{CategoryImages, CategoryFonts, CategoryContent, CategoryAttachments, CategoryMetadata} {
+		categories[name] = &SizeCategory{Name: name}
+	}
+
+	var objects []ObjectSize
+	for objNr, entry := range pdfCtx.Table {
+		if objNr == 0 || entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok {
+			continue
+		}
+
+		size := streamSize(sd)
+		category, description := "", ""
+		switch {
+		case isSubtype(sd.Dict, "Image"):
+			category = CategoryImages
+			image := a.image(objNr, sd)
+			image.Bytes = size
+			analysis.Images = append(analysis.Images, *image)
+			description = fmt.Sprintf("%d×%d image (%s)", image.Width, image.Height, image.Filter)
+		case fonts[objNr] != nil:
+			category = CategoryFonts
+			font := fonts[objNr]
+			font.Bytes = size
+			analysis.Fonts = append(analysis.Fonts, *font)
+			description = fmt.Sprintf("%s font %s", font.Format, font.Name)
+		case a.content[objNr] || isSubtype(sd.Dict, "Form"):
+			category = CategoryContent
+			description = "content stream"
+		case isType(sd.Dict, "EmbeddedFile"):
+			category = CategoryAttachments
+			description = "attached file"
+		case isType(sd.Dict, "Metadata"):
+			category = CategoryMetadata
+			description = "XMP metadata"
+		default:
+			continue
+		}
+
+		categories[category].Bytes += size
+		categories[category].Count++
+		objects = append(objects, ObjectSize{ObjectNumber: objNr, Category: category, Description: description, Bytes: size})
+	}
+
+	// The Info dictionary is metadata too, though it isn't a stream; count its serialized size
+	if pdfCtx.Info != nil {
+		if infoDict, err := pdfCtx.DereferenceDict(*pdfCtx.Info); err == nil && infoDict != nil {
+			size := int64(len(infoDict.PDFString()))
+			categories[CategoryMetadata].Bytes += size
+			categories[CategoryMetadata].Count++
+			objects = append(objects, ObjectSize{ObjectNumber: pdfCtx.Info.ObjectNumber.Value(), Category: CategoryMetadata, Description: "document info", Bytes: size})
+		}
+	}
+
+	var categorized int64
+	for _, name := range []string{CategoryImages, CategoryFonts, CategoryContent, CategoryAttachments, CategoryMetadata} {
+		analysis.Categories = append(analysis.Categories, *categories[name])
+		categorized += categories[name].Bytes
+	}
+	analysis.Categories = append(analysis.Categories, SizeCategory{Name: CategoryOther, Bytes: max(0, analysis.FileSize-categorized)})
+	for i := range analysis.Categories {
+		analysis.Categories[i].Percent = percentOf(analysis.Categories[i].Bytes, analysis.FileSize)
+	}
+
+	bySizeDesc := func(a, b int64) int { return cmp.Compare(b, a) }
+	slices.SortFunc(analysis.Images, func(a, b ImageAnalysis) int { return bySizeDesc(a.Bytes, b.Bytes) })
+	slices.SortFunc(analysis.Fonts, func(a, b FontAnalysis) int { return bySizeDesc(a.Bytes, b.Bytes) })
+	slices.SortFunc(objects, func(a, b ObjectSize) int { return bySizeDesc(a.Bytes, b.Bytes) })
+	analysis.LargestObjects = objects[:min(len(objects), largestObjectsCount)]
+
+	analysis.RecommendedPreset, analysis.Recommendation = recommendPreset(analysis)
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Analyzed %s: %d%% images, %d%% fonts, recommended preset: %s",
+		info.Name(), analysis.Categories[0].Percent, analysis.Categories[1].Percent, analysis.RecommendedPreset))
+
+	return analysis, nil
+}
+
+// recommendPreset picks the preset most likely to shrink the file without needless quality loss
+func recommendPreset(analysis *PDFAnalysis) (CompressionPreset, string) {
+	// Weight resolution by size, so a sharp icon doesn't outvote the scans
+	var imageBytes, placedBytes, weightedDPI int64
+	for _, image := range analysis.Images {
+		imageBytes += image.Bytes
+		if image.DPI > 0 {
+			placedBytes += image.Bytes
+			weightedDPI += int64(image.DPI) * image.Bytes
+		}
+	}
+	dpi := 0
+	if placedBytes > 0 {
+		dpi = int(weightedDPI / placedBytes)
+	}
+
+	switch {
+	case analysis.FileSize == 0 || imageBytes*4 < analysis.FileSize:
+		return PresetLossless, "Images are a small part of the file, so lossless optimization is the safe choice"
+	case dpi == 0 || dpi > 150:
+		return PresetEbook, fmt.Sprintf("Images make up %d%% of the file; downsampling them to 150 dpi keeps them sharp on screen",
+			percentOf(imageBytes, analysis.FileSize))
+	case dpi > 100:
+		// Screen quality would still shrink these, but only at a visible cost, so it stays the user's call
+		return PresetLossless, fmt.Sprintf("Images make up %d%% of the file but are already around %d dpi; lossless optimization keeps them as they are, "+
+			"and the screen preset (72 dpi) only makes sense if the smallest file matters more than image quality",
+			percentOf(imageBytes, analysis.FileSize), dpi)
+	default:
+		return PresetLossless, fmt.Sprintf("Images are already low resolution (around %d dpi); lossless optimization avoids degrading them further", dpi)
+	}
+}
+
+// analyzer collects what AnalyzePDF learns by walking the page tree
+type analyzer struct {
+	pdfCtx  *model.Context
+	content map[int]bool           // page content stream objects
+	images  map[int]*ImageAnalysis // image objects found on a page, by object number
+	visited map[int]bool           // form XObjects already scanned
+}
+
+// walkPages records each page's content streams and where and how large each image is first drawn
+func (a *analyzer) walkPages() error {
+	for pageNum := 1; pageNum <= a.pdfCtx.PageCount; pageNum++ {
+		pageDict, _, inherited, err := a.pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return err
+		}
+
+		var contentRefs []types.IndirectRef
+		switch contents := pageDict["Contents"].(type) {
+		case types.IndirectRef:
+			contentRefs = append(contentRefs, contents)
+		case types.Array:
+			for _, o := range contents {
+				if ir, ok := o.(types.IndirectRef); ok {
+					contentRefs = append(contentRefs, ir)
+				}
+			}
+		}
+
+		// A page's content may be split across streams at any token boundary
+		var content []byte
+		for _, ir := range contentRefs {
+			a.content[ir.ObjectNumber.Value()] = true
+			if data := a.decodedStream(ir); data != nil {
+				content = append(append(content, data...), '\n')
+			}
+		}
+
+		resources, _ := a.pdfCtx.DereferenceDict(pageDict["Resources"])
+		if resources == nil && inherited != nil {
+			resources = inherited.Resources
+		}
+		a.scanDraws(content, resources, identityMatrix, pageNum, 0)
+	}
+	return nil
+}
+
+// scanDraws records the images a content stream draws, following form XObjects into their own content
+func (a *analyzer) scanDraws(content []byte, resources types.Dict, ctm matrix, pageNum, depth int) {
+	if resources == nil || depth > maxFormDepth {
+		return
+	}
+	xObjects, err := a.pdfCtx.DereferenceDict(resources["XObject"])
+	if err != nil || xObjects == nil {
+		return
+	}
+
+	scanXObjectDraws(content, ctm, func(name string, ctm matrix) {
+		ir, ok := xObjects[name].(types.IndirectRef)
+		if !ok {
+			return
+		}
+		objNr := ir.ObjectNumber.Value()
+		sd, _, err := a.pdfCtx.DereferenceStreamDict(ir)
+		if err != nil || sd == nil {
+			return
+		}
+
+		switch {
+		case isSubtype(sd.Dict, "Image"):
+			if _, seen := a.images[objNr]; !seen {
+				image := a.image(objNr, *sd)
+				image.Page = pageNum
+				image.DPI = effectiveDPI(image.Width, image.Height, ctm)
+				a.images[objNr] = image
+			}
+		case isSubtype(sd.Dict, "Form"):
+			if a.visited[objNr] {
+				return
+			}
+			a.visited[objNr] = true
+
+			formResources, _ := a.pdfCtx.DereferenceDict(sd.Dict["Resources"])
+			if formResources == nil {
+				formResources = resources
+			}
+			formMatrix := identityMatrix
+			if m := sd.ArrayEntry("Matrix"); len(m) == 6 {
+				for i, v := range m {
+					if f, ok := numberValue(v); ok {
+						formMatrix[i] = f
+					}
+				}
+			}
+			if data := a.decodedStream(ir); data != nil {
+				a.scanDraws(data, formResources, formMatrix.multiply(ctm), pageNum, depth+1)
+			}
+		}
+	})
+}
+
+// decodedStream returns a stream's decoded content, or nil if it can't be decoded
+func (a *analyzer) decodedStream(ir types.IndirectRef) []byte {
+	sd, _, err := a.pdfCtx.DereferenceStreamDict(ir)
+	if err != nil || sd == nil {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	return sd.Content
+}
+
+// image describes an image stream, reusing what walkPages found about its placement
+func (a *analyzer) image(objNr int, sd types.StreamDict) *ImageAnalysis {
+	if image, ok := a.images[objNr]; ok {
+		return image
+	}
+
+	image := &ImageAnalysis{ObjectNumber: objNr}
+	if w := sd.IntEntry("Width"); w != nil {
+		image.Width = *w
+	}
+	if h := sd.IntEntry("Height"); h != nil {
+		image.Height = *h
+	}
+
+	var filters []string
+	for _, f := range sd.FilterPipeline {
+		filters = append(filters, f.Name)
+	}
+	image.Filter = strings.Join(filters, " + ")
+	if image.Filter == "" {
+		image.Filter = "none"
+	}
+
+	if cs, err := a.pdfCtx.Dereference(sd.Dict["ColorSpace"]); err == nil {
+		switch cs := cs.(type) {
+		case types.Name:
+			image.ColorSpace = cs.Value()
+		case types.Array:
+			if len(cs) > 0 {
+				if name, ok := cs[0].(types.Name); ok {
+					image.ColorSpace = name.Value()
+				}
+			}
+		}
+	}
+	if isTrue(sd.Dict, "ImageMask") {
+		image.ColorSpace = "mask"
+	}
+	return image
+}
+
+// fontPrograms maps embedded font program streams to the fonts they belong to
+func (a *analyzer) fontPrograms() map[int]*FontAnalysis {
+	fonts := make(map[int]*FontAnalysis)
+	formats := []struct{ key, format string }{
+		{"FontFile", "Type1"},
+		{"FontFile2", "TrueType"},
+		{"FontFile3", "CFF/OpenType"},
+	}
+
+	for _, entry := range a.pdfCtx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		d, ok := entry.Object.(types.Dict)
+		if !ok || !isType(d, "FontDescriptor") {
+			continue
+		}
+
+		name := ""
+		if n := d.NameEntry("FontName"); n != nil {
+			name = *n
+		}
+		for _, f := range formats {
+			if ir := d.IndirectRefEntry(f.key); ir != nil {
+				fonts[ir.ObjectNumber.Value()] = &FontAnalysis{ObjectNumber: ir.ObjectNumber.Value(), Name: name, Format: f.format}
+			}
+		}
+	}
+	return fonts
+}
+
+// effectiveDPI is an image's resolution as drawn with ctm, in pixels per inch along
+// its sharper axis; 0 if the image is drawn with no size
+func effectiveDPI(width, height int, ctm matrix) int {
+	w, h := ctm.unitSize()
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return int(math.Round(max(float64(width)*72/w, float64(height)*72/h)))
+}
+
+// numberValue converts a PDF integer or real to float64
+func numberValue(o types.Object) (float64, bool) {
+	switch v := o.(type) {
+	case types.Integer:
+		return float64(v), true
+	case types.Float:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// streamSize is the stored length of a stream, as it takes up space in the file
+func streamSize(sd types.StreamDict) int64 {
+	if sd.StreamLength != nil {
+		return *sd.StreamLength
+	}
+	return int64(len(sd.Raw))
+}
+
+func isType(d types.Dict, want string) bool {
+	t := d.Type()
+	return t != nil && *t == want
+}
+
+func isSubtype(d types.Dict, want string) bool {
+	t := d.Subtype()
+	return t != nil && *t == want
+}
+
+func isTrue(d types.Dict, key string) bool {
+	b := d.BooleanEntry(key)
+	return b != nil && *b
+}
+
+// percentOf returns part as a whole-number percentage of total
+func percentOf(part, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(part * 100 / total)
+}
@@ -0,0 +1,169 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+func TestAnalyzePDF(t *testing.T) {
+	fixtures := []string{"simple-1page.pdf", "multi-page.pdf", "high-res-images.pdf"}
+
+	for _, name := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			fixturePath := filepath.Join("..", "test", "fixtures", "valid", name)
+			if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+				t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+			}
+
+			analysis, err := AnalyzePDF(mockContext(), fixturePath)
+			if err != nil {
+				t.Fatalf("AnalyzePDF() error = %v", err)
+			}
+
+			info, _ := os.Stat(fixturePath)
+			if analysis.FileSize != info.Size() {
+				t.Errorf("FileSize = %d, want %d", analysis.FileSize, info.Size())
+			}
+			if analysis.PageCount < 1 {
+				t.Errorf("PageCount = %d, want >= 1", analysis.PageCount)
+			}
+
+			// The categories account for the whole file
+			var total int64
+			for _, c := range analysis.Categories {
+				total += c.Bytes
+			}
+			if total != analysis.FileSize {
+				t.Errorf("category bytes sum to %d, want file size %d", total, analysis.FileSize)
+			}
+
+			for i := 1; i < len(analysis.LargestObjects); i++ {
+				if analysis.LargestObjects[i].Bytes > analysis.LargestObjects[i-1].Bytes {
+					t.Errorf("LargestObjects not sorted by size at %d", i)
+				}
+			}
+			if len(analysis.LargestObjects) > largestObjectsCount {
+				t.Errorf("got %d largest objects, want at most %d", len(analysis.LargestObjects), largestObjectsCount)
+			}
+			if analysis.RecommendedPreset == "" || analysis.Recommendation == "" {
+				t.Error("missing preset recommendation")
+			}
+		})
+	}
+}
+
+func TestAnalyzePDF_Images(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "high-res-images.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	analysis, err := AnalyzePDF(mockContext(), fixturePath)
+	if err != nil {
+		t.Fatalf("AnalyzePDF() error = %v", err)
+	}
+	if len(analysis.Images) == 0 {
+		t.Skip("Fixture has no images")
+	}
+
+	for _, image := range analysis.Images {
+		if image.Width <= 0 || image.Height <= 0 {
+			t.Errorf("image %d has no dimensions", image.ObjectNumber)
+		}
+		if image.Page > 0 && image.DPI <= 0 {
+			t.Errorf("image %d on page %d has no DPI estimate", image.ObjectNumber, image.Page)
+		}
+	}
+	if analysis.Categories[0].Name != CategoryImages || analysis.Categories[0].Count != len(analysis.Images) {
+		t.Errorf("images category = %+v, want %d images", analysis.Categories[0], len(analysis.Images))
+	}
+}
+
+func TestAnalyzePDF_InfoMetadata(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	pdfCtx, err := api.ReadContextFile(fixturePath)
+	if err != nil {
+		t.Fatalf("Failed to read PDF: %v", err)
+	}
+	if pdfCtx.Info == nil {
+		t.Skip("Fixture has no Info dictionary")
+	}
+
+	analysis, err := AnalyzePDF(mockContext(), fixturePath)
+	if err != nil {
+		t.Fatalf("AnalyzePDF() error = %v", err)
+	}
+	for _, c := range analysis.Categories {
+		if c.Name == CategoryMetadata && (c.Count == 0 || c.Bytes == 0) {
+			t.Errorf("metadata category = %+v, want the Info dictionary counted", c)
+		}
+	}
+}
+
+func TestAnalyzePDF_InvalidInput(t *testing.T) {
+	if _, err := AnalyzePDF(mockContext(), "/nonexistent/file.pdf"); err == nil {
+		t.Error("AnalyzePDF() should return error for non-existent file")
+	}
+
+	notPDF := filepath.Join("..", "test", "fixtures", "edge-cases", "not-a-pdf.pdf")
+	if _, err := os.Stat(notPDF); err == nil {
+		if _, err := AnalyzePDF(mockContext(), notPDF); err == nil {
+			t.Error("AnalyzePDF() should return error for non-PDF file")
+		}
+	}
+}
+
+func TestRecommendPreset(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis PDFAnalysis
+		want     CompressionPreset
+	}{
+		{"text only", PDFAnalysis{FileSize: 1000}, PresetLossless},
+		{"few images", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{DPI: 600, Bytes: 100}}}, PresetLossless},
+		{"high-res scans", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{DPI: 600, Bytes: 900}}}, PresetEbook},
+		{"above ebook resolution", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{DPI: 200, Bytes: 900}}}, PresetEbook},
+		{"medium-res images", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{DPI: 150, Bytes: 900}}}, PresetLossless},
+		{"low-res images", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{DPI: 72, Bytes: 900}}}, PresetLossless},
+		{"unplaced images", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{Bytes: 900}}}, PresetEbook},
+		{"large scan outweighs sharp icon", PDFAnalysis{FileSize: 1000, Images: []ImageAnalysis{{DPI: 600, Bytes: 10}, {DPI: 72, Bytes: 890}}}, PresetLossless},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := recommendPreset(&tt.analysis); got != tt.want {
+				t.Errorf("recommendPreset() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveDPI(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		ctm           matrix
+		want          int
+	}{
+		{"full letter page at 300 dpi", 2550, 3300, matrix{612, 0, 0, 792, 0, 0}, 300},
+		{"1-inch logo", 300, 300, matrix{72, 0, 0, 72, 100, 100}, 300},
+		{"rotated 90°", 1275, 1650, matrix{0, 612, -792, 0, 612, 0}, 150},
+		{"distorted: sharper axis wins", 600, 100, matrix{72, 0, 0, 72, 0, 0}, 600},
+		{"zero size", 100, 100, matrix{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := effectiveDPI(tt.width, tt.height, tt.ctm); got != tt.want {
+				t.Errorf("effectiveDPI() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// CompressBatch compresses many files with one preset, running up to concurrency files at once
+// (0 means one per CPU). Each file reports "compress:batch:file" events keyed by its ID, and
+// "compress:progress" tracks the batch as a whole. A failing file doesn't stop the others;
+// its error is recorded in the result. The caller owns all output files.
+func CompressBatch(ctx context.Context, paths []string, preset CompressionPreset, concurrency int) (*BatchCompressionResult, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files to compress")
+	}
+	if concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency: %d", concurrency)
+	}
+	if concurrency == 0 {
+		concurrency = runtime.NumCPU()
+	}
+	concurrency = min(concurrency, len(paths))
+
+	gsPath, err := ghostscriptForPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &BatchCompressionResult{Files: make([]BatchFileResult, len(paths))}
+	for i, path := range paths {
+		batch.Files[i] = BatchFileResult{ID: GenerateID(), Path: path}
+		safeEmit(ctx, "compress:batch:file", BatchFileProgress{FileID: batch.Files[i].ID, Path: path, Message: "Queued"})
+	}
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 0,
+		Message: fmt.Sprintf("Compressing %d files...", len(paths)),
+	})
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Compressing %d files with preset %s, %d at a time", len(paths), preset, concurrency))
+
+	var (
+		mu       sync.Mutex
+		finished int
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan int)
+
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				file := &batch.Files[i]
+
+				// Once the batch is cancelled, the remaining files fail without starting
+				var compressed *CompressionResult
+				err := ctx.Err()
+				if err == nil {
+					compressed, err = compressBatchFile(ctx, gsPath, file.ID, file.Path, preset)
+				}
+
+				progress := BatchFileProgress{FileID: file.ID, Path: file.Path, Percent: 100, Done: true}
+				if err != nil {
+					file.Error = err.Error()
+					progress.Message = "Failed"
+					progress.Error = file.Error
+				} else {
+					file.Result = compressed
+					progress.Message = fmt.Sprintf("Saved %d%%", compressed.SavingsPercent)
+				}
+				safeEmit(ctx, "compress:batch:file", progress)
+
+				mu.Lock()
+				finished++
+				if err != nil {
+					safeEmit(ctx, "compress:log", fmt.Sprintf("%s: failed: %v", filepath.Base(file.Path), err))
+				} else {
+					safeEmit(ctx, "compress:log", fmt.Sprintf("%s: %s -> %s (saved %d%%)", filepath.Base(file.Path),
+						FormatFileSize(compressed.OriginalSize), FormatFileSize(compressed.CompressedSize), compressed.SavingsPercent))
+				}
+				safeEmit(ctx, "compress:progress", ProgressUpdate{
+					Percent: 100 * finished / len(paths),
+					Message: fmt.Sprintf("Compressed %d of %d files...", finished, len(paths)),
+				})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, file := range batch.Files {
+		if file.Result == nil {
+			batch.Failed++
+			continue
+		}
+		batch.Succeeded++
+		batch.TotalOriginalSize += file.Result.OriginalSize
+		batch.TotalCompressedSize += file.Result.CompressedSize
+	}
+	if batch.TotalOriginalSize > 0 {
+		batch.SavingsPercent = int(100 - (batch.TotalCompressedSize * 100 / batch.TotalOriginalSize))
+	}
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Batch complete: %d succeeded, %d failed, saved %d%% overall",
+		batch.Succeeded, batch.Failed, batch.SavingsPercent))
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return batch, nil
+}
+
+// compressBatchFile compresses one file of a batch, reporting its progress under id
+func compressBatchFile(ctx context.Context, gsPath, id, path string, preset CompressionPreset) (*CompressionResult, error) {
+	emit := func(percent int, message string) {
+		safeEmit(ctx, "compress:batch:file", BatchFileProgress{FileID: id, Path: path, Percent: percent, Message: message})
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	emit(5, "Compressing...")
+
+	var onPage func(page int)
+	if pageCount, err := api.PageCountFile(path); err == nil && pageCount > 0 {
+		onPage = func(page int) {
+			page = min(page, pageCount)
+			emit(5+90*page/pageCount, fmt.Sprintf("Compressing page %d of %d...", page, pageCount))
+		}
+	}
+	result, err := compressWithPreset(ctx, gsPath, path, info.Size(), preset, onPage)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Preset = preset
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package pdf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+func TestCompressBatch(t *testing.T) {
+	fixtures := []string{"simple-1page.pdf", "multi-page.pdf", "high-res-images.pdf"}
+	var paths []string
+	for _, name := range fixtures {
+		path := filepath.Join("..", "test", "fixtures", "valid", name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+		}
+		paths = append(paths, path)
+	}
+
+	batch, err := CompressBatch(mockContext(), paths, PresetEbook, 2)
+	if err != nil {
+		t.Fatalf("CompressBatch() error = %v", err)
+	}
+	for _, file := range batch.Files {
+		if file.Result != nil {
+			defer CleanupTempFiles(file.Result.OutputPath)
+		}
+	}
+
+	if batch.Succeeded != len(paths) || batch.Failed != 0 {
+		t.Errorf("Succeeded = %d, Failed = %d, want %d and 0", batch.Succeeded, batch.Failed, len(paths))
+	}
+	for i, file := range batch.Files {
+		if file.Path != paths[i] {
+			t.Errorf("Files[%d].Path = %s, want %s (input order)", i, file.Path, paths[i])
+		}
+		if file.Result == nil {
+			t.Errorf("%s failed: %s", file.Path, file.Error)
+			continue
+		}
+		if err := api.ValidateFile(file.Result.OutputPath, nil); err != nil {
+			t.Errorf("%s: output is not valid PDF: %v", file.Path, err)
+		}
+	}
+}
+
+func TestCompressBatch_ContinuesPastFailures(t *testing.T) {
+	valid := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(valid); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+	notPDF := filepath.Join("..", "test", "fixtures", "edge-cases", "not-a-pdf.pdf")
+
+	// Lossless needs no Ghostscript
+	paths := []string{"/nonexistent/file.pdf", valid, notPDF, valid}
+	batch, err := CompressBatch(mockContext(), paths, PresetLossless, 3)
+	if err != nil {
+		t.Fatalf("CompressBatch() error = %v", err)
+	}
+	for _, file := range batch.Files {
+		if file.Result != nil {
+			defer CleanupTempFiles(file.Result.OutputPath)
+		}
+	}
+
+	if batch.Succeeded != 2 || batch.Failed != 2 {
+		t.Errorf("Succeeded = %d, Failed = %d, want 2 and 2", batch.Succeeded, batch.Failed)
+	}
+	for i, wantOK := range []bool{false, true, false, true} {
+		file := batch.Files[i]
+		if (file.Result != nil) != wantOK || (file.Error == "") != wantOK {
+			t.Errorf("Files[%d] = result %v, error %q; want success %v", i, file.Result != nil, file.Error, wantOK)
+		}
+	}
+
+	ids := map[string]bool{}
+	for _, file := range batch.Files {
+		if file.ID == "" || ids[file.ID] {
+			t.Errorf("file ID %q is empty or duplicated", file.ID)
+		}
+		ids[file.ID] = true
+	}
+	if batch.TotalOriginalSize <= 0 || batch.TotalCompressedSize <= 0 {
+		t.Errorf("totals not computed: %+v", batch)
+	}
+}
+
+func TestCompressBatch_Cancelled(t *testing.T) {
+	valid := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(valid); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	ctx, cancel := context.WithCancel(mockContext())
+	cancel()
+
+	batch, err := CompressBatch(ctx, []string{valid, valid}, PresetLossless, 1)
+	if err != nil {
+		t.Fatalf("CompressBatch() error = %v", err)
+	}
+	if batch.Failed != 2 {
+		t.Errorf("Failed = %d, want 2 after cancellation", batch.Failed)
+	}
+}
+
+func TestCompressBatch_InvalidInput(t *testing.T) {
+	if _, err := CompressBatch(mockContext(), nil, PresetLossless, 1); err == nil {
+		t.Error("CompressBatch() should return error for no files")
+	}
+	if _, err := CompressBatch(mockContext(), []string{"a.pdf"}, PresetLossless, -1); err == nil {
+		t.Error("CompressBatch() should return error for negative concurrency")
+	}
+}
@@ -1,11 +1,16 @@
 package pdf
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 // CombinePDFs merges multiple PDF files into one
@@ -19,14 +24,12 @@ func CombinePDFs(ctx context.Context, documents []PDFDocument) (*CombineResult,
 		Message: fmt.Sprintf("Preparing to combine %d files...", len(documents)),
 	})
 
-	// Collect all input paths
-	var inputPaths []string
-	totalPages := 0
-
 	for _, doc := range documents {
-		safeEmit(ctx, "combine:log", fmt.Sprintf("Adding: %s (%d pages)", doc.Name, doc.PageCount))
-		inputPaths = append(inputPaths, doc.Path)
-		totalPages += doc.PageCount
+		if len(doc.PageOrder) > 0 {
+			safeEmit(ctx, "combine:log", fmt.Sprintf("Adding: %s (%d of %d pages, custom order)", doc.Name, len(doc.PageOrder), doc.PageCount))
+		} else {
+			safeEmit(ctx, "combine:log", fmt.Sprintf("Adding: %s (%d pages)", doc.Name, doc.PageCount))
+		}
 	}
 
 	// Create temp output file
@@ -40,12 +43,19 @@ func CombinePDFs(ctx context.Context, documents []PDFDocument) (*CombineResult,
 		Message: "Merging PDF files...",
 	})
 
-	// Merge the PDFs
-	if err := api.MergeCreateFile(inputPaths, outputPath, false, nil); err != nil {
+	// Merge the PDFs, applying any custom page order
+	if err := mergeDocuments(documents, outputPath); err != nil {
 		CleanupTempFiles(outputPath)
 		return nil, fmt.Errorf("merge failed: %w", err)
 	}
 
+	// Count pages in the output, since page orders may drop or repeat pages
+	totalPages, err := api.PageCountFile(outputPath)
+	if err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot read output file: %w", err)
+	}
+
 	safeEmit(ctx, "combine:progress", ProgressUpdate{
 		Percent: 80,
 		Message: "Finalizing...",
@@ -75,76 +85,238 @@ func CombinePDFs(ctx context.Context, documents []PDFDocument) (*CombineResult,
 	}, nil
 }
 
-// MergeTwoFiles merges two PDFs with the specified mode (interleave or append)
-func MergeTwoFiles(ctx context.Context, pathA, pathB string, mode MergeMode) (*PDFDocument, error) {
-	safeEmit(ctx, "combine:log", fmt.Sprintf("Merging two files with mode: %s", mode))
-
-	// Create temp output file
-	outputPath, err := CreateTempFile("merged", ".pdf")
+// mergeDocuments merges documents into outputPath in order, with a bookmark per document.
+// Documents with a PageOrder are rebuilt in memory, so no intermediate temp files are written.
+func mergeDocuments(documents []PDFDocument, outputPath string) error {
+	pdfCtx, err := mergeContexts(documents)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create temp file: %w", err)
+		return err
 	}
+	return api.WriteContextFile(pdfCtx, outputPath)
+}
+
+// mergeContexts merges documents in memory and returns the combined context
+func mergeContexts(documents []PDFDocument) (*model.Context, error) {
+	conf := model.NewDefaultConfiguration()
+	conf.Cmd = model.MERGECREATE
+	conf.ValidationMode = model.ValidationRelaxed
 
-	if mode == MergeModeInterleave {
-		// Get page counts
-		countA, err := api.PageCountFile(pathA)
+	var ctxDest *model.Context
+	for _, doc := range documents {
+		ctxSrc, err := readDocumentContext(doc, conf)
 		if err != nil {
-			return nil, fmt.Errorf("cannot read first PDF: %w", err)
+			return nil, fmt.Errorf("cannot read %s: %w", doc.Name, err)
 		}
-		countB, err := api.PageCountFile(pathB)
-		if err != nil {
-			return nil, fmt.Errorf("cannot read second PDF: %w", err)
+
+		if ctxDest == nil {
+			ctxDest = ctxSrc
+			if err := pdfcpu.EnsureOutlines(ctxDest, doc.Name, false); err != nil {
+				return nil, err
+			}
+			ctxDest.EnsureVersionForWriting()
+			continue
 		}
 
-		safeEmit(ctx, "combine:log", fmt.Sprintf("Interleaving %d + %d pages", countA, countB))
+		if ctxDest.XRefTable.Version() < model.V20 && ctxSrc.XRefTable.Version() == model.V20 {
+			return nil, pdfcpu.ErrUnsupportedVersion
+		}
+		if err := pdfcpu.MergeXRefTables(doc.Name, ctxSrc, ctxDest, false, false); err != nil {
+			return nil, fmt.Errorf("cannot merge %s: %w", doc.Name, err)
+		}
+	}
+
+	// Like pdfcpu's own merge, drop the fonts and images the documents have in common
+	if err := api.OptimizeContext(ctxDest); err != nil {
+		return nil, fmt.Errorf("cannot optimize merged PDF: %w", err)
+	}
+
+	return ctxDest, nil
+}
+
+// readDocumentContext reads a document, applying its PageOrder if it has one
+func readDocumentContext(doc PDFDocument, conf *model.Configuration) (*model.Context, error) {
+	f, err := os.Open(doc.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pdfCtx, err := api.ReadAndValidate(f, conf)
+	if err != nil || len(doc.PageOrder) == 0 {
+		return pdfCtx, err
+	}
+
+	for _, pageNum := range doc.PageOrder {
+		if pageNum < 1 || pageNum > pdfCtx.PageCount {
+			return nil, fmt.Errorf("page %d out of range (1-%d)", pageNum, pdfCtx.PageCount)
+		}
+	}
+
+	data, err := renderPages(pdfCtx, doc.PageOrder)
+	if err != nil {
+		return nil, err
+	}
+	return api.ReadAndValidate(bytes.NewReader(data), conf)
+}
+
+// MergeTwoFiles merges two PDFs with the specified mode (interleave, interleave-reverse or append)
+func MergeTwoFiles(ctx context.Context, pathA, pathB string, mode MergeMode) (*PDFDocument, error) {
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Merging two files with mode: %s", mode))
 
-		// Efficient interleave: first merge both PDFs, then reorder pages
-		// After merge, PDF A pages are 1..countA, PDF B pages are (countA+1)..(countA+countB)
-		mergedPath, err := CreateTempFile("merged_temp", ".pdf")
+	switch mode {
+	case MergeModeInterleave:
+		return mergeInterleaved(ctx, pathA, pathB, false, false)
+	case MergeModeInterleaveReverse:
+		return mergeInterleaved(ctx, pathA, pathB, true, false)
+	default:
+		// Simple append
+		outputPath, err := CreateTempFile("merged", ".pdf")
 		if err != nil {
-			CleanupTempFiles(outputPath)
 			return nil, fmt.Errorf("cannot create temp file: %w", err)
 		}
+		return mergeTwoAppend(ctx, pathA, pathB, outputPath)
+	}
+}
 
-		// Merge both PDFs
-		if err := api.MergeCreateFile([]string{pathA, pathB}, mergedPath, false, nil); err != nil {
-			CleanupTempFiles(outputPath, mergedPath)
-			return nil, fmt.Errorf("merge failed: %w", err)
+// MergeDuplexScan assembles a double-sided document from two single-sided scans:
+// frontsPath holds the front sides in order, backsPath the back sides in reverse order
+// (the stack flipped over). When blankBacks is set, fronts without a matching back get
+// a blank back page so every sheet occupies two pages.
+func MergeDuplexScan(ctx context.Context, frontsPath, backsPath string, blankBacks bool) (*PDFDocument, error) {
+	safeEmit(ctx, "combine:log", "Merging duplex scan")
+	return mergeInterleaved(ctx, frontsPath, backsPath, true, blankBacks)
+}
+
+// mergeInterleaved alternates pages from A and B (1A, 1B, 2A, 2B, ...).
+// With reverseB, B is read back to front. Leftover pages of the longer file are appended,
+// unless blankBacks is set, in which case A pages without a partner are followed by a blank page.
+func mergeInterleaved(ctx context.Context, pathA, pathB string, reverseB, blankBacks bool) (*PDFDocument, error) {
+	// Efficient interleave: first merge both PDFs in memory, then reorder pages
+	// After merge, PDF A pages are 1..countA, PDF B pages are (countA+1)..(countA+countB)
+	merged, err := mergeContexts([]PDFDocument{
+		{Path: pathA, Name: filepath.Base(pathA)},
+		{Path: pathB, Name: filepath.Base(pathB)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("merge failed: %w", err)
+	}
+
+	countA, err := api.PageCountFile(pathA)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read first PDF: %w", err)
+	}
+	countB := merged.PageCount - countA
+
+	if reverseB {
+		safeEmit(ctx, "combine:log", fmt.Sprintf("Interleaving %d + %d pages (second file reversed)", countA, countB))
+	} else {
+		safeEmit(ctx, "combine:log", fmt.Sprintf("Interleaving %d + %d pages", countA, countB))
+	}
+
+	pageOrder, blankAfter := interleaveOrder(countA, countB, reverseB, blankBacks)
+
+	// Page sizes come from the merged document; an extracted context doesn't report them
+	mergedDims, err := merged.PageDims()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read page sizes: %w", err)
+	}
+
+	pdfCtx, err := pdfcpu.ExtractPages(merged, pageOrder, false)
+	if err != nil {
+		return nil, fmt.Errorf("interleave failed: %w", err)
+	}
+
+	if len(blankAfter) > 0 {
+		safeEmit(ctx, "combine:log", fmt.Sprintf("Adding %d blank back pages", len(blankAfter)))
+		// Each blank page matches the front it follows
+		dims := make([]types.Dim, len(pageOrder))
+		for i, pageNum := range pageOrder {
+			dims[i] = mergedDims[pageNum-1]
+		}
+		if err := insertBlankPagesAfter(pdfCtx, dims, blankAfter); err != nil {
+			return nil, fmt.Errorf("cannot insert blank pages: %w", err)
 		}
+	}
+
+	// Create temp output file
+	outputPath, err := CreateTempFile("merged", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
 
-		// Build interleaved page order
-		// Pages from A: 1, 2, ..., countA
-		// Pages from B: countA+1, countA+2, ..., countA+countB
-		// Interleaved: 1, countA+1, 2, countA+2, ...
-		var pageOrder []string
-		maxPages := countA
-		if countB > maxPages {
-			maxPages = countB
+	if err := api.WriteContextFile(pdfCtx, outputPath); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("interleave failed: %w", err)
+	}
+
+	// Get info about merged file
+	return GetPDFInfo(outputPath)
+}
+
+// interleaveOrder builds the page order for interleaving A (pages 1..countA) with
+// B (pages countA+1..countA+countB), e.g. 1, countA+1, 2, countA+2, ...
+// or 1, countA+countB, 2, countA+countB-1, ... when reverseB is set.
+// blankAfter holds the output positions that should be followed by a blank page.
+func interleaveOrder(countA, countB int, reverseB, blankBacks bool) (pageOrder []int, blankAfter types.IntSet) {
+	blankAfter = types.IntSet{}
+	for i := 1; i <= max(countA, countB); i++ {
+		if i <= countA {
+			pageOrder = append(pageOrder, i)
+		}
+		switch {
+		case i <= countB && reverseB:
+			pageOrder = append(pageOrder, countA+countB+1-i)
+		case i <= countB:
+			pageOrder = append(pageOrder, countA+i)
+		case blankBacks:
+			blankAfter[len(pageOrder)] = true
 		}
+	}
+	return pageOrder, blankAfter
+}
+
+// insertBlankPagesAfter adds a blank page after each page in after, sized like that page
+// according to dims. pdfcpu inserts one size per pass, so pages are grouped by size and
+// later passes account for the blank pages added by earlier ones.
+func insertBlankPagesAfter(pdfCtx *model.Context, dims []types.Dim, after types.IntSet) error {
+	var sizes []types.Dim
+	groups := make(map[types.Dim][]int)
+	for pageNum := 1; pageNum <= len(dims); pageNum++ {
+		if !after[pageNum] {
+			continue
+		}
+		dim := dims[pageNum-1]
+		if _, ok := groups[dim]; !ok {
+			sizes = append(sizes, dim)
+		}
+		groups[dim] = append(groups[dim], pageNum)
+	}
 
-		for i := 1; i <= maxPages; i++ {
-			if i <= countA {
-				pageOrder = append(pageOrder, fmt.Sprintf("%d", i))
+	// inserted[p] is set once original page p has its blank page
+	inserted := make([]bool, len(dims)+1)
+	for _, dim := range sizes {
+		current := types.IntSet{}
+		shift := 0
+		next := 0
+		pages := groups[dim]
+		for pageNum := 1; pageNum <= len(dims) && next < len(pages); pageNum++ {
+			if pageNum == pages[next] {
+				current[pageNum+shift] = true
+				next++
 			}
-			if i <= countB {
-				pageOrder = append(pageOrder, fmt.Sprintf("%d", countA+i))
+			if inserted[pageNum] {
+				shift++
 			}
 		}
 
-		// Use CollectFile to reorder pages
-		if err := api.CollectFile(mergedPath, outputPath, pageOrder, nil); err != nil {
-			CleanupTempFiles(outputPath, mergedPath)
-			return nil, fmt.Errorf("interleave failed: %w", err)
+		if err := pdfCtx.InsertBlankPages(current, &dim, false); err != nil {
+			return err
+		}
+		for _, pageNum := range pages {
+			inserted[pageNum] = true
 		}
-
-		CleanupTempFiles(mergedPath)
-	} else {
-		// Simple append
-		return mergeTwoAppend(ctx, pathA, pathB, outputPath)
 	}
-
-	// Get info about merged file
-	return GetPDFInfo(outputPath)
+	return nil
 }
 
 func mergeTwoAppend(ctx context.Context, pathA, pathB, outputPath string) (*PDFDocument, error) {
@@ -158,31 +330,67 @@ func mergeTwoAppend(ctx context.Context, pathA, pathB, outputPath string) (*PDFD
 	return GetPDFInfo(outputPath)
 }
 
-// ReorderPages creates a new PDF with pages in the specified order
-func ReorderPages(ctx context.Context, path string, pageOrder []int) (*PDFDocument, error) {
-	if len(pageOrder) == 0 {
-		return nil, fmt.Errorf("page order cannot be empty")
+// InsertPDF inserts pages of sourcePath into targetPath after page afterPage (0 inserts at the front).
+// sourcePages selects and orders the source pages (1-indexed); empty means all pages.
+func InsertPDF(ctx context.Context, targetPath, sourcePath string, afterPage int, sourcePages []int) (*PDFDocument, error) {
+	targetCount, err := api.PageCountFile(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read target PDF: %w", err)
+	}
+	if afterPage < 0 || afterPage > targetCount {
+		return nil, fmt.Errorf("insert position %d out of range (0-%d)", afterPage, targetCount)
 	}
 
-	safeEmit(ctx, "combine:log", fmt.Sprintf("Reordering %d pages", len(pageOrder)))
+	// Merge target and source in memory; the source's pages follow the target's
+	merged, err := mergeContexts([]PDFDocument{
+		{Path: targetPath, Name: filepath.Base(targetPath)},
+		{Path: sourcePath, Name: filepath.Base(sourcePath), PageOrder: sourcePages},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("merge failed: %w", err)
+	}
+	sourceCount := merged.PageCount - targetCount
 
-	// Create temp output file
-	outputPath, err := CreateTempFile("reordered", ".pdf")
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Inserting %d pages after page %d of %d", sourceCount, afterPage, targetCount))
+
+	pageOrder := append(pageSequence(1, afterPage), pageSequence(targetCount+1, targetCount+sourceCount)...)
+	pageOrder = append(pageOrder, pageSequence(afterPage+1, targetCount)...)
+
+	pdfCtx, err := pdfcpu.ExtractPages(merged, pageOrder, false)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create temp file: %w", err)
+		return nil, fmt.Errorf("insert failed: %w", err)
 	}
 
-	// Build page selection string for pdfcpu
-	var pageSelections []string
-	for _, pageNum := range pageOrder {
-		pageSelections = append(pageSelections, fmt.Sprintf("%d", pageNum))
+	// Create temp output file
+	outputPath, err := CreateTempFile("inserted", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
 	}
 
-	// Use pdfcpu to collect pages in new order
-	if err := api.CollectFile(path, outputPath, pageSelections, nil); err != nil {
+	if err := api.WriteContextFile(pdfCtx, outputPath); err != nil {
 		CleanupTempFiles(outputPath)
-		return nil, fmt.Errorf("reorder failed: %w", err)
+		return nil, fmt.Errorf("insert failed: %w", err)
 	}
 
 	return GetPDFInfo(outputPath)
 }
+
+// ReorderPages creates a new PDF with pages in the specified order
+func ReorderPages(ctx context.Context, path string, pageOrder []int) (*PDFDocument, error) {
+	if len(pageOrder) == 0 {
+		return nil, fmt.Errorf("page order cannot be empty")
+	}
+
+	doc, err := GetPDFInfo(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePageNumbers(pageOrder, doc.PageCount); err != nil {
+		return nil, err
+	}
+
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Reordering %d pages", len(pageOrder)))
+
+	// Use pdfcpu to collect pages in new order
+	return collectPages(path, "reordered", pageOrder)
+}
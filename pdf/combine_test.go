@@ -1,11 +1,14 @@
 package pdf
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 func TestCombinePDFs_TwoFiles(t *testing.T) {
@@ -111,6 +114,120 @@ func TestCombinePDFs_SingleFile(t *testing.T) {
 	}
 }
 
+func TestCombinePDFs_PageOrder(t *testing.T) {
+	multiPage := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	singlePage := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(multiPage); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	doc1, err := GetPDFInfo(multiPage)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+	doc2, err := GetPDFInfo(singlePage)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+
+	// Drop every page but 1 and 3, reverse them and duplicate page 1
+	doc1.PageOrder = []int{3, 1, 1}
+
+	result, err := CombinePDFs(mockContext(), []PDFDocument{*doc1, *doc2})
+	if err != nil {
+		t.Fatalf("CombinePDFs() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+
+	expectedPages := len(doc1.PageOrder) + doc2.PageCount
+	outputPages, _ := api.PageCountFile(result.OutputPath)
+	if outputPages != expectedPages {
+		t.Errorf("Output page count = %d, want %d", outputPages, expectedPages)
+	}
+	if result.PageCount != expectedPages {
+		t.Errorf("CombinePDFs().PageCount = %d, want %d", result.PageCount, expectedPages)
+	}
+}
+
+func TestCombinePDFs_ReorderedDocument(t *testing.T) {
+	multiPage := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	singlePage := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(multiPage); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	// The Edit Pages dialog swaps in the reordered file, which already has its pages in order
+	reordered, err := ReorderPages(mockContext(), multiPage, []int{3, 1})
+	if err != nil {
+		t.Fatalf("ReorderPages() error = %v", err)
+	}
+	defer CleanupTempFiles(reordered.Path)
+	if len(reordered.PageOrder) != 0 {
+		t.Fatalf("reordered document has PageOrder %v, want none", reordered.PageOrder)
+	}
+
+	doc2, err := GetPDFInfo(singlePage)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+
+	result, err := CombinePDFs(mockContext(), []PDFDocument{*reordered, *doc2})
+	if err != nil {
+		t.Fatalf("CombinePDFs() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if result.PageCount != 2+doc2.PageCount {
+		t.Errorf("CombinePDFs().PageCount = %d, want %d", result.PageCount, 2+doc2.PageCount)
+	}
+	// The order isn't applied a second time: the output starts with original page 3
+	if got, want := pageContent(t, result.OutputPath, 1), pageContent(t, multiPage, 3); !bytes.Equal(got, want) {
+		t.Error("first page of the combined PDF doesn't match original page 3")
+	}
+}
+
+// pageContent returns the decoded content stream of a page
+func pageContent(t *testing.T, path string, pageNum int) []byte {
+	t.Helper()
+	pdfCtx, err := api.ReadContextFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	d, _, _, err := pdfCtx.PageDict(pageNum, false)
+	if err != nil {
+		t.Fatalf("Failed to get page %d: %v", pageNum, err)
+	}
+	content, err := pdfCtx.PageContent(d, pageNum)
+	if err != nil {
+		t.Fatalf("Failed to read content of page %d: %v", pageNum, err)
+	}
+	return content
+}
+
+func TestCombinePDFs_PageOrderOutOfRange(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	doc, err := GetPDFInfo(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+
+	reordered := *doc
+	reordered.PageOrder = []int{doc.PageCount + 1}
+
+	_, err = CombinePDFs(mockContext(), []PDFDocument{*doc, reordered})
+	if err == nil {
+		t.Error("CombinePDFs() should return error for out-of-range page order")
+	}
+}
+
 func TestMergeTwoFiles_Append(t *testing.T) {
 	fixture1 := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
 	fixture2 := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
@@ -165,6 +282,161 @@ func TestMergeTwoFiles_Interleave(t *testing.T) {
 	}
 }
 
+func TestMergeTwoFiles_InterleaveReverse(t *testing.T) {
+	fixture1 := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	fixture2 := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+
+	if _, err := os.Stat(fixture1); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	merged, err := MergeTwoFiles(mockContext(), fixture1, fixture2, MergeModeInterleaveReverse)
+	if err != nil {
+		t.Fatalf("MergeTwoFiles() error = %v", err)
+	}
+	defer CleanupTempFiles(merged.Path)
+
+	if err := api.ValidateFile(merged.Path, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+
+	pages1, _ := api.PageCountFile(fixture1)
+	pages2, _ := api.PageCountFile(fixture2)
+	if merged.PageCount != pages1+pages2 {
+		t.Errorf("Merged page count = %d, want %d", merged.PageCount, pages1+pages2)
+	}
+}
+
+func TestMergeDuplexScan_BlankBacks(t *testing.T) {
+	fronts := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	backs := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+
+	if _, err := os.Stat(fronts); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	merged, err := MergeDuplexScan(mockContext(), fronts, backs, true)
+	if err != nil {
+		t.Fatalf("MergeDuplexScan() error = %v", err)
+	}
+	defer CleanupTempFiles(merged.Path)
+
+	if err := api.ValidateFile(merged.Path, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+
+	// Every front gets a back, scanned or blank
+	frontPages, _ := api.PageCountFile(fronts)
+	if merged.PageCount != frontPages*2 {
+		t.Errorf("Merged page count = %d, want %d", merged.PageCount, frontPages*2)
+	}
+
+	// Blank backs match the size of their front
+	frontDims, _ := api.PageDimsFile(fronts)
+	dims, err := api.PageDimsFile(merged.Path)
+	if err != nil {
+		t.Fatalf("Failed to get page dimensions: %v", err)
+	}
+	last := len(dims) - 1
+	if dims[last] != frontDims[len(frontDims)-1] {
+		t.Errorf("Blank back size = %v, want %v", dims[last], frontDims[len(frontDims)-1])
+	}
+}
+
+func TestInterleaveOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		countA, countB int
+		reverseB       bool
+		blankBacks     bool
+		wantOrder      []int
+		wantBlankAfter []int
+	}{
+		{"interleave", 3, 3, false, false, []int{1, 4, 2, 5, 3, 6}, nil},
+		{"reverse", 3, 3, true, false, []int{1, 6, 2, 5, 3, 4}, nil},
+		{"uneven appends leftovers", 3, 1, true, false, []int{1, 4, 2, 3}, nil},
+		{"more backs than fronts", 1, 3, true, false, []int{1, 4, 3, 2}, nil},
+		{"blank backs", 3, 1, true, true, []int{1, 4, 2, 3}, []int{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, blankAfter := interleaveOrder(tt.countA, tt.countB, tt.reverseB, tt.blankBacks)
+			if fmt.Sprint(order) != fmt.Sprint(tt.wantOrder) {
+				t.Errorf("interleaveOrder() order = %v, want %v", order, tt.wantOrder)
+			}
+			if len(blankAfter) != len(tt.wantBlankAfter) {
+				t.Errorf("interleaveOrder() blankAfter = %v, want %v", blankAfter, tt.wantBlankAfter)
+			}
+			for _, pos := range tt.wantBlankAfter {
+				if !blankAfter[pos] {
+					t.Errorf("interleaveOrder() missing blank page after position %d", pos)
+				}
+			}
+		})
+	}
+}
+
+func TestInsertPDF(t *testing.T) {
+	target := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	source := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	targetPages, _ := api.PageCountFile(target)
+	sourcePages, _ := api.PageCountFile(source)
+
+	tests := []struct {
+		name        string
+		afterPage   int
+		sourcePages []int
+		wantPages   int
+	}{
+		{"front", 0, nil, targetPages + sourcePages},
+		{"middle", 2, nil, targetPages + sourcePages},
+		{"end", targetPages, nil, targetPages + sourcePages},
+		{"selected pages", 1, []int{1, 1}, targetPages + 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := InsertPDF(mockContext(), target, source, tt.afterPage, tt.sourcePages)
+			if err != nil {
+				t.Fatalf("InsertPDF() error = %v", err)
+			}
+			defer CleanupTempFiles(result.Path)
+
+			if err := api.ValidateFile(result.Path, nil); err != nil {
+				t.Errorf("Output is not valid PDF: %v", err)
+			}
+			if result.PageCount != tt.wantPages {
+				t.Errorf("InsertPDF() page count = %d, want %d", result.PageCount, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestInsertPDF_InvalidInput(t *testing.T) {
+	target := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	source := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	targetPages, _ := api.PageCountFile(target)
+
+	if _, err := InsertPDF(mockContext(), target, source, targetPages+1, nil); err == nil {
+		t.Error("InsertPDF() should return error for position past the end")
+	}
+	if _, err := InsertPDF(mockContext(), target, source, -1, nil); err == nil {
+		t.Error("InsertPDF() should return error for negative position")
+	}
+	if _, err := InsertPDF(mockContext(), target, source, 0, []int{2}); err == nil {
+		t.Error("InsertPDF() should return error for out-of-range source page")
+	}
+}
+
 func TestReorderPages_Simple(t *testing.T) {
 	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
 	if _, err := os.Stat(fixture); os.IsNotExist(err) {
@@ -262,6 +534,53 @@ func TestCombinePDFs_OutputSize(t *testing.T) {
 	}
 }
 
+func TestCombinePDFs_SharedFonts(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalFonts := countFontObjects(t, fixture)
+	if originalFonts == 0 {
+		t.Skip("Fixture has no fonts")
+	}
+
+	doc, err := GetPDFInfo(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+	result, err := CombinePDFs(mockContext(), []PDFDocument{*doc, *doc})
+	if err != nil {
+		t.Fatalf("CombinePDFs() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	// The second copy's fonts are duplicates of the first's and should be dropped
+	if got := countFontObjects(t, result.OutputPath); got != originalFonts {
+		t.Errorf("combined PDF has %d font objects, want %d", got, originalFonts)
+	}
+}
+
+// countFontObjects counts the font dictionaries in a PDF
+func countFontObjects(t *testing.T, path string) int {
+	t.Helper()
+	pdfCtx, err := api.ReadContextFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	count := 0
+	for _, entry := range pdfCtx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		if d, ok := entry.Object.(types.Dict); ok && d.Type() != nil && *d.Type() == "Font" {
+			count++
+		}
+	}
+	return count
+}
+
 func BenchmarkCombinePDFs(b *testing.B) {
 	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
 	if _, err := os.Stat(fixture); os.IsNotExist(err) {
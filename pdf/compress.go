@@ -1,27 +1,84 @@
 package pdf
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
+	"io"
+	"math/bits"
 	"os"
 	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
 )
 
-// gsPresetSettings maps compression presets to Ghostscript -dPDFSETTINGS values
-var gsPresetSettings = map[CompressionPreset]struct {
-	setting     string
-	description string
-}{
-	PresetScreen:   {setting: "/screen", description: "Screen quality (72 dpi, smallest)"},
-	PresetEbook:    {setting: "/ebook", description: "eBook quality (150 dpi)"},
-	PresetPrinter:  {setting: "/printer", description: "Printer quality (300 dpi)"},
-	PresetPrepress: {setting: "/prepress", description: "Prepress quality (300 dpi, color preserving)"},
-	PresetDefault:  {setting: "/default", description: "Default quality"},
+// presetOptions are the named compression profiles. Each picks a Ghostscript
+// -dPDFSETTINGS base, so image resolutions follow Ghostscript's defaults for it.
+var presetOptions = map[CompressionPreset]CompressionOptions{
+	PresetScreen:   {Description: "Screen quality (72 dpi, smallest)", PDFSettings: "screen", CompatibilityLevel: "1.4", SubsetFonts: true},
+	PresetEbook:    {Description: "eBook quality (150 dpi)", PDFSettings: "ebook", CompatibilityLevel: "1.4", SubsetFonts: true},
+	PresetPrinter:  {Description: "Printer quality (300 dpi)", PDFSettings: "printer", CompatibilityLevel: "1.4", SubsetFonts: true},
+	PresetPrepress: {Description: "Prepress quality (300 dpi, color preserving)", PDFSettings: "prepress", CompatibilityLevel: "1.4", SubsetFonts: true},
+	PresetDefault:  {Description: "Default quality", PDFSettings: "default", CompatibilityLevel: "1.4", SubsetFonts: true},
+}
+
+// validPDFSettings are the Ghostscript -dPDFSETTINGS profiles
+var validPDFSettings = []string{"screen", "ebook", "printer", "prepress", "default"}
+
+// validCompatibilityLevels are the PDF versions Ghostscript's pdfwrite can produce
+var validCompatibilityLevels = []string{"1.3", "1.4", "1.5", "1.6", "1.7", "2.0"}
+
+// OptionsForPreset returns the Ghostscript options for a named preset, falling back to PresetDefault.
+// PresetLossless doesn't use Ghostscript, so it has no options.
+func OptionsForPreset(preset CompressionPreset) (CompressionOptions, error) {
+	if preset == PresetLossless {
+		return CompressionOptions{}, fmt.Errorf("preset %q doesn't use Ghostscript options; use CompressPDF", preset)
+	}
+	opts, ok := presetOptions[preset]
+	if !ok {
+		opts = presetOptions[PresetDefault]
+	}
+	return opts, nil
 }
 
-// CompressPDF compresses a PDF file using Ghostscript with the given preset
+// presetOrder lists the presets from smallest to largest expected output
+var presetOrder = []CompressionPreset{PresetScreen, PresetEbook, PresetDefault, PresetPrinter, PresetPrepress, PresetLossless}
+
+// CompressPDF compresses a PDF file using Ghostscript with the given preset.
+// PresetLossless uses pdfcpu's optimizer instead and doesn't need Ghostscript.
 func CompressPDF(ctx context.Context, inputPath string, preset CompressionPreset) (*CompressionResult, error) {
+	if preset == PresetLossless {
+		return compressLossless(ctx, inputPath)
+	}
+
+	opts, err := OptionsForPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+	result, err := CompressPDFWithOptions(ctx, inputPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.Method == MethodGhostscript {
+		result.Preset = preset
+	}
+	return result, nil
+}
+
+// CompressPDFWithOptions compresses a PDF file using Ghostscript with a custom profile
+func CompressPDFWithOptions(ctx context.Context, inputPath string, opts CompressionOptions) (*CompressionResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Get original file size
 	originalInfo, err := os.Stat(inputPath)
 	if err != nil {
@@ -48,74 +105,448 @@ func CompressPDF(ctx context.Context, inputPath string, preset CompressionPreset
 	}
 	safeEmit(ctx, "compress:log", fmt.Sprintf("Using Ghostscript: %s", gsPath))
 
-	// Get preset config
-	config, ok := gsPresetSettings[preset]
-	if !ok {
-		config = gsPresetSettings[PresetDefault]
+	if opts.Description != "" {
+		safeEmit(ctx, "compress:log", fmt.Sprintf("Using preset: %s", opts.Description))
+	} else {
+		safeEmit(ctx, "compress:log", fmt.Sprintf("Using custom settings: %s", opts.summary()))
 	}
-	safeEmit(ctx, "compress:log", fmt.Sprintf("Using preset: %s", config.description))
 
-	// Create temp output file
-	outputPath, err := CreateTempFile("compressed", ".pdf")
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 20,
+		Message: "Running Ghostscript compression...",
+	})
+
+	safeEmit(ctx, "compress:log", "Running Ghostscript...")
+
+	// The page count turns Ghostscript's per-page output into a percentage.
+	// Ghostscript may read files pdfcpu can't, so without a count there's just no per-page progress.
+	var onPage func(page int)
+	if pageCount, err := api.PageCountFile(inputPath); err == nil && pageCount > 0 {
+		onPage = func(page int) {
+			page = min(page, pageCount)
+			safeEmit(ctx, "compress:progress", ProgressUpdate{
+				Percent: 20 + 70*page/pageCount,
+				Message: fmt.Sprintf("Compressing page %d of %d...", page, pageCount),
+			})
+		}
+	}
+
+	if opts.StripMetadata {
+		safeEmit(ctx, "compress:log", "Document metadata will be removed")
+	}
+
+	result, err := compressWithGhostscript(ctx, gsPath, inputPath, originalSize, opts, onPage)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create temp file: %w", err)
+		return nil, err
 	}
 
 	safeEmit(ctx, "compress:progress", ProgressUpdate{
-		Percent: 20,
-		Message: "Running Ghostscript compression...",
+		Percent: 90,
+		Message: "Finalizing...",
+	})
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Original: %s, Compressed: %s", FormatFileSize(originalSize), FormatFileSize(result.CompressedSize)))
+
+	switch {
+	case result.CompressedSize >= originalSize && opts.SkipIfLarger:
+		safeEmit(ctx, "compress:log", "Ghostscript did not reduce the file size, trying lossless optimization instead...")
+		gsOutput := result.OutputPath
+		if result, err = fallbackCompression(inputPath, originalSize); err != nil {
+			CleanupTempFiles(gsOutput)
+			return nil, err
+		}
+		CleanupTempFiles(gsOutput)
+		if opts.StripMetadata {
+			if err := stripMetadata(result.OutputPath); err != nil {
+				CleanupTempFiles(result.OutputPath)
+				return nil, fmt.Errorf("cannot remove metadata: %w", err)
+			}
+			if result, err = newCompressionResult(originalSize, result.OutputPath, result.Method); err != nil {
+				return nil, err
+			}
+		}
+		if result.Method == MethodOptimize {
+			safeEmit(ctx, "compress:log", fmt.Sprintf("Optimized: %s (saved %d%%)", FormatFileSize(result.CompressedSize), result.SavingsPercent))
+		} else {
+			safeEmit(ctx, "compress:log", "Keeping the original file; it is already as small as it gets")
+		}
+	case result.SavingsPercent < 0:
+		safeEmit(ctx, "compress:log", "Warning: Compressed file is larger than original")
+	default:
+		safeEmit(ctx, "compress:log", fmt.Sprintf("Saved: %d%%", result.SavingsPercent))
+	}
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
 	})
 
-	// Build Ghostscript command
+	return result, nil
+}
+
+// CompressAllPresets compresses a PDF with every preset concurrently and returns the results
+// ranked from smallest to largest output, so the user can pick one. Presets that fail are
+// listed last with Success false; an error is returned only if every preset fails.
+// The caller owns all returned output files.
+func CompressAllPresets(ctx context.Context, inputPath string) ([]CompressionResult, error) {
+	originalInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+	originalSize := originalInfo.Size()
+	if originalSize == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 10,
+		Message: "Preparing compression...",
+	})
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Input file: %s (%s), trying %d presets",
+		originalInfo.Name(), FormatFileSize(originalSize), len(presetOrder)))
+
+	results := make([]CompressionResult, len(presetOrder))
+	var (
+		mu       sync.Mutex
+		finished int
+		wg       sync.WaitGroup
+	)
+	// Each Ghostscript run is single-threaded, so one per CPU
+	slots := make(chan struct{}, min(len(presetOrder), runtime.NumCPU()))
+
+	for i, preset := range presetOrder {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			// Without Ghostscript, only the lossless preset succeeds
+			var result *CompressionResult
+			gsPath, err := ghostscriptForPreset(preset)
+			if err == nil {
+				result, err = compressWithPreset(ctx, gsPath, inputPath, originalSize, preset, nil)
+			}
+			if err != nil {
+				result = &CompressionResult{OriginalSize: originalSize, Error: err.Error()}
+			}
+			result.Preset = preset
+			results[i] = *result
+
+			mu.Lock()
+			defer mu.Unlock()
+			finished++
+			if err != nil {
+				safeEmit(ctx, "compress:log", fmt.Sprintf("%s: failed: %v", preset, err))
+			} else {
+				safeEmit(ctx, "compress:log", fmt.Sprintf("%s: %s (saved %d%%)", preset, FormatFileSize(result.CompressedSize), result.SavingsPercent))
+			}
+			safeEmit(ctx, "compress:progress", ProgressUpdate{
+				Percent: 10 + 85*finished/len(presetOrder),
+				Message: fmt.Sprintf("Compressed with %d of %d presets...", finished, len(presetOrder)),
+			})
+		}()
+	}
+	wg.Wait()
+
+	rankCompressionResults(results)
+	if !results[0].Success {
+		return nil, fmt.Errorf("all presets failed: %s", results[0].Error)
+	}
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return results, nil
+}
+
+// rankCompressionResults sorts successful results by output size, smallest first, followed by failures
+func rankCompressionResults(results []CompressionResult) {
+	slices.SortStableFunc(results, func(a, b CompressionResult) int {
+		if a.Success != b.Success {
+			if a.Success {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.CompressedSize, b.CompressedSize)
+	})
+}
+
+// ghostscriptForPreset finds Ghostscript if preset needs it; PresetLossless doesn't
+func ghostscriptForPreset(preset CompressionPreset) (string, error) {
+	if preset == PresetLossless {
+		return "", nil
+	}
+	gsPath, err := GetGhostscriptPath()
+	if err != nil {
+		return "", fmt.Errorf("ghostscript not available: %w. %s", err, GhostscriptInstallInstructions())
+	}
+	return gsPath, nil
+}
+
+// compressWithPreset compresses inputPath into a new temp file with a named preset:
+// pdfcpu's optimizer for PresetLossless, Ghostscript at gsPath for the others
+func compressWithPreset(ctx context.Context, gsPath, inputPath string, originalSize int64, preset CompressionPreset, onPage func(page int)) (*CompressionResult, error) {
+	if preset == PresetLossless {
+		return optimizeLossless(inputPath, originalSize)
+	}
+	opts, err := OptionsForPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+	return compressWithGhostscript(ctx, gsPath, inputPath, originalSize, opts, onPage)
+}
+
+// compressWithGhostscript runs one Ghostscript pass over inputPath into a new temp file
+func compressWithGhostscript(ctx context.Context, gsPath, inputPath string, originalSize int64, opts CompressionOptions, onPage func(page int)) (*CompressionResult, error) {
+	outputPath, err := CreateTempFile("compressed", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	if err := runGhostscript(ctx, gsPath, ghostscriptArgs(opts, inputPath, outputPath), onPage); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, err
+	}
+
+	if opts.StripMetadata {
+		if err := stripMetadata(outputPath); err != nil {
+			CleanupTempFiles(outputPath)
+			return nil, fmt.Errorf("cannot remove metadata: %w", err)
+		}
+	}
+
+	result, err := newCompressionResult(originalSize, outputPath, MethodGhostscript)
+	if err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, err
+	}
+	return result, nil
+}
+
+// fallbackCompression is used when Ghostscript made a file bigger. It runs pdfcpu's lossless
+// optimization and keeps that if it is smaller, otherwise it returns an unchanged copy of the original.
+func fallbackCompression(inputPath string, originalSize int64) (*CompressionResult, error) {
+	// Optimization failing just means falling back to the original
+	if result, err := optimizeLossless(inputPath, originalSize); err == nil {
+		if result.CompressedSize < originalSize {
+			return result, nil
+		}
+		CleanupTempFiles(result.OutputPath)
+	}
+
+	outputPath, err := CreateTempFile("compressed", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+	if err := CopyFile(inputPath, outputPath); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot copy original: %w", err)
+	}
+	result, err := newCompressionResult(originalSize, outputPath, MethodOriginal)
+	if err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Validate checks the options before Ghostscript is started
+func (o CompressionOptions) Validate() error {
+	if o.PDFSettings != "" && !slices.Contains(validPDFSettings, o.PDFSettings) {
+		return fmt.Errorf("invalid compression options: unknown base profile %q", o.PDFSettings)
+	}
+	for _, res := range []struct {
+		name string
+		dpi  int
+	}{
+		{"color image resolution", o.ColorImageResolution},
+		{"gray image resolution", o.GrayImageResolution},
+		{"mono image resolution", o.MonoImageResolution},
+	} {
+		if res.dpi != 0 && (res.dpi < 10 || res.dpi > 2400) {
+			return fmt.Errorf("invalid compression options: %s must be between 10 and 2400 dpi, got %d", res.name, res.dpi)
+		}
+	}
+	if o.DownsampleThreshold != 0 && (o.DownsampleThreshold < 1 || o.DownsampleThreshold > 10) {
+		return fmt.Errorf("invalid compression options: downsample threshold must be between 1.0 and 10.0, got %g", o.DownsampleThreshold)
+	}
+	if o.JPEGQuality < 0 || o.JPEGQuality > 100 {
+		return fmt.Errorf("invalid compression options: JPEG quality must be between 1 and 100, got %d", o.JPEGQuality)
+	}
+	if o.CompatibilityLevel != "" && !slices.Contains(validCompatibilityLevels, o.CompatibilityLevel) {
+		return fmt.Errorf("invalid compression options: unsupported PDF version %q", o.CompatibilityLevel)
+	}
+	return nil
+}
+
+// summary describes the options for the log
+func (o CompressionOptions) summary() string {
+	var parts []string
+	if o.PDFSettings != "" {
+		parts = append(parts, "base "+o.PDFSettings)
+	}
+	if o.ColorImageResolution > 0 {
+		parts = append(parts, fmt.Sprintf("color %d dpi", o.ColorImageResolution))
+	}
+	if o.GrayImageResolution > 0 {
+		parts = append(parts, fmt.Sprintf("gray %d dpi", o.GrayImageResolution))
+	}
+	if o.MonoImageResolution > 0 {
+		parts = append(parts, fmt.Sprintf("mono %d dpi", o.MonoImageResolution))
+	}
+	if o.JPEGQuality > 0 {
+		parts = append(parts, fmt.Sprintf("JPEG quality %d", o.JPEGQuality))
+	}
+	if len(parts) == 0 {
+		return "Ghostscript defaults"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ghostscriptArgs maps compression options to Ghostscript pdfwrite arguments
+func ghostscriptArgs(opts CompressionOptions, inputPath, outputPath string) []string {
+	settings := opts.PDFSettings
+	if settings == "" {
+		settings = "default"
+	}
+	compatibility := opts.CompatibilityLevel
+	if compatibility == "" {
+		compatibility = "1.4"
+	}
+
+	// Not quiet: Ghostscript's "Page N" lines drive progress reporting
 	args := []string{
-		"-q",                // Quiet mode
 		"-dNOPAUSE",         // Don't pause between pages
 		"-dBATCH",           // Exit after processing
 		"-dSAFER",           // Restrict file operations
 		"-sDEVICE=pdfwrite", // Output device
-		"-dCompatibilityLevel=1.4",
-		fmt.Sprintf("-dPDFSETTINGS=%s", config.setting),
+		"-dCompatibilityLevel=" + compatibility,
+		"-dPDFSETTINGS=/" + settings,
 		"-dEmbedAllFonts=true",
-		"-dSubsetFonts=true",
+		fmt.Sprintf("-dSubsetFonts=%t", opts.SubsetFonts),
 		"-dCompressFonts=true",
 		"-dColorImageDownsampleType=/Bicubic",
 		"-dGrayImageDownsampleType=/Bicubic",
 		"-dMonoImageDownsampleType=/Bicubic",
-		fmt.Sprintf("-sOutputFile=%s", outputPath),
-		inputPath,
 	}
 
-	safeEmit(ctx, "compress:log", "Running Ghostscript...")
+	for _, image := range []struct {
+		kind string
+		dpi  int
+	}{
+		{"Color", opts.ColorImageResolution},
+		{"Gray", opts.GrayImageResolution},
+		{"Mono", opts.MonoImageResolution},
+	} {
+		if image.dpi == 0 {
+			continue
+		}
+		args = append(args,
+			fmt.Sprintf("-dDownsample%sImages=true", image.kind),
+			fmt.Sprintf("-d%sImageResolution=%d", image.kind, image.dpi),
+		)
+		if opts.DownsampleThreshold > 0 {
+			args = append(args, fmt.Sprintf("-d%sImageDownsampleThreshold=%.2f", image.kind, opts.DownsampleThreshold))
+		}
+	}
+
+	args = append(args, fmt.Sprintf("-sOutputFile=%s", outputPath))
+
+	if opts.JPEGQuality > 0 {
+		// Force JPEG so the quality factor applies
+		imageDict := fmt.Sprintf("<< /QFactor %.2f /Blend 1 /HSamples [2 1 1 2] /VSamples [2 1 1 2] >>", jpegQFactor(opts.JPEGQuality))
+		args = append(args,
+			"-dAutoFilterColorImages=false",
+			"-dAutoFilterGrayImages=false",
+			"-dColorImageFilter=/DCTEncode",
+			"-dGrayImageFilter=/DCTEncode",
+			"-c", fmt.Sprintf("<< /ColorImageDict %s /GrayImageDict %s >> setdistillerparams", imageDict, imageDict),
+			"-f",
+		)
+	}
+
+	return append(args, inputPath)
+}
+
+// stripMetadata removes the document info and XMP metadata from a PDF in place
+func stripMetadata(path string) error {
+	pdfCtx, err := api.ReadContextFile(path)
+	if err != nil {
+		return err
+	}
+
+	// pdfcpu writes a fresh info dict with only the producer and dates
+	pdfCtx.Info = nil
+	delete(pdfCtx.RootDict, "Metadata")
 
-	// Execute Ghostscript
+	strippedPath, err := CreateTempFile("stripped", ".pdf")
+	if err != nil {
+		return err
+	}
+	if err := api.WriteContextFile(pdfCtx, strippedPath); err != nil {
+		CleanupTempFiles(strippedPath)
+		return err
+	}
+	return os.Rename(strippedPath, path)
+}
+
+// runGhostscript runs gs with args, reporting its stderr output on failure.
+// If onPage is set, it is called for each "Page N" line Ghostscript prints as it works.
+func runGhostscript(ctx context.Context, gsPath string, args []string, onPage func(page int)) error {
 	cmd := exec.CommandContext(ctx, gsPath, args...)
 	hideWindow(cmd) // Hide console window on Windows
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	safeEmit(ctx, "compress:progress", ProgressUpdate{
-		Percent: 50,
-		Message: "Compressing PDF...",
-	})
+	var stdout io.ReadCloser
+	if onPage != nil {
+		var err error
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return fmt.Errorf("cannot run ghostscript: %w", err)
+		}
+	}
 
-	if err := cmd.Run(); err != nil {
-		CleanupTempFiles(outputPath)
-		errMsg := stderr.String()
-		if errMsg != "" {
-			return nil, fmt.Errorf("ghostscript failed: %s", errMsg)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ghostscript failed: %w", err)
+	}
+	if stdout != nil {
+		scanGhostscriptPages(stdout, onPage)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if errMsg := stderr.String(); errMsg != "" {
+			return fmt.Errorf("ghostscript failed: %s", errMsg)
 		}
-		return nil, fmt.Errorf("ghostscript failed: %w", err)
+		return fmt.Errorf("ghostscript failed: %w", err)
 	}
+	return nil
+}
 
-	safeEmit(ctx, "compress:progress", ProgressUpdate{
-		Percent: 90,
-		Message: "Finalizing...",
-	})
+// gsPageLine matches the line Ghostscript prints as it starts each page
+var gsPageLine = regexp.MustCompile(`^Page (\d+)`)
+
+// scanGhostscriptPages reads Ghostscript's stdout until EOF, calling onPage for each page started
+func scanGhostscriptPages(r io.Reader, onPage func(page int)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if m := gsPageLine.FindStringSubmatch(scanner.Text()); m != nil {
+			if page, err := strconv.Atoi(m[1]); err == nil {
+				onPage(page)
+			}
+		}
+	}
+	// Drain anything left (e.g. after an over-long line) so Ghostscript never blocks on a full pipe
+	io.Copy(io.Discard, r)
+}
 
-	// Get compressed file size
+// newCompressionResult measures outputPath and computes the savings against originalSize
+func newCompressionResult(originalSize int64, outputPath string, method CompressionMethod) (*CompressionResult, error) {
 	compressedInfo, err := os.Stat(outputPath)
 	if err != nil {
-		CleanupTempFiles(outputPath)
 		return nil, fmt.Errorf("cannot read compressed file: %w", err)
 	}
 	compressedSize := compressedInfo.Size()
@@ -126,24 +557,174 @@ func CompressPDF(ctx context.Context, inputPath string, preset CompressionPreset
 		savingsPercent = int(100 - (compressedSize * 100 / originalSize))
 	}
 
-	safeEmit(ctx, "compress:log", fmt.Sprintf("Original: %s, Compressed: %s", FormatFileSize(originalSize), FormatFileSize(compressedSize)))
+	return &CompressionResult{
+		Success:        true,
+		OriginalSize:   originalSize,
+		CompressedSize: compressedSize,
+		SavingsPercent: savingsPercent,
+		OutputPath:     outputPath,
+		Method:         method,
+	}, nil
+}
+
+// imageQuality is one step of CompressToTarget's search: image resolution in dpi and JPEG quality (1-100)
+type imageQuality struct {
+	resolution  int
+	jpegQuality int
+}
+
+// targetQualityLadder lists the settings CompressToTarget searches, from best to worst quality.
+// The last entry is the quality floor.
+var targetQualityLadder = []imageQuality{
+	{resolution: 300, jpegQuality: 90},
+	{resolution: 225, jpegQuality: 85},
+	{resolution: 150, jpegQuality: 80},
+	{resolution: 120, jpegQuality: 70},
+	{resolution: 96, jpegQuality: 60},
+	{resolution: 72, jpegQuality: 50},
+	{resolution: 50, jpegQuality: 40},
+}
+
+// CompressToTarget compresses a PDF to at most targetBytes, keeping as much image quality as possible.
+// It searches targetQualityLadder, lowering image resolution and JPEG quality until the output fits.
+// If even the quality floor is too large, the smallest result is returned and a warning is logged.
+func CompressToTarget(ctx context.Context, inputPath string, targetBytes int64) (*CompressionResult, error) {
+	if targetBytes <= 0 {
+		return nil, fmt.Errorf("target size must be positive")
+	}
+
+	originalInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+	originalSize := originalInfo.Size()
+	if originalSize == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
 
-	if savingsPercent < 0 {
-		safeEmit(ctx, "compress:log", "Warning: Compressed file is larger than original")
-	} else {
-		safeEmit(ctx, "compress:log", fmt.Sprintf("Saved: %d%%", savingsPercent))
+	gsPath, err := GetGhostscriptPath()
+	if err != nil {
+		return nil, fmt.Errorf("ghostscript not available: %w. %s", err, GhostscriptInstallInstructions())
 	}
 
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 10,
+		Message: "Preparing compression...",
+	})
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Input file: %s (%s), target: %s",
+		originalInfo.Name(), FormatFileSize(originalSize), FormatFileSize(targetBytes)))
+
+	// Each attempt's output, by ladder index; all but the chosen one are removed at the end
+	attempts := make(map[int]*CompressionResult)
+	defer func() {
+		for _, attempt := range attempts {
+			CleanupTempFiles(attempt.OutputPath)
+		}
+	}()
+
+	maxAttempts := bits.Len(uint(len(targetQualityLadder)))
+	try := func(i int) (int64, error) {
+		quality := targetQualityLadder[i]
+
+		safeEmit(ctx, "compress:progress", ProgressUpdate{
+			Percent: 10 + 80*len(attempts)/maxAttempts,
+			Message: fmt.Sprintf("Trying %d dpi...", quality.resolution),
+		})
+
+		outputPath, err := CreateTempFile("compressed", ".pdf")
+		if err != nil {
+			return 0, fmt.Errorf("cannot create temp file: %w", err)
+		}
+		if err := runGhostscript(ctx, gsPath, ghostscriptArgs(targetOptions(quality), inputPath, outputPath), nil); err != nil {
+			CleanupTempFiles(outputPath)
+			return 0, err
+		}
+
+		result, err := newCompressionResult(originalSize, outputPath, MethodGhostscript)
+		if err != nil {
+			CleanupTempFiles(outputPath)
+			return 0, err
+		}
+		attempts[i] = result
+
+		verdict := "fits"
+		if result.CompressedSize > targetBytes {
+			verdict = "over target"
+		}
+		safeEmit(ctx, "compress:log", fmt.Sprintf("Attempt %d: %d dpi, JPEG quality %d -> %s (%s)",
+			len(attempts), quality.resolution, quality.jpegQuality, FormatFileSize(result.CompressedSize), verdict))
+
+		return result.CompressedSize, nil
+	}
+
+	best, err := searchQualityLadder(len(targetQualityLadder), targetBytes, try)
+	if err != nil {
+		return nil, err
+	}
+
+	if best < 0 {
+		// Nothing fit: fall back to the smallest output
+		for i, attempt := range attempts {
+			if best < 0 || attempt.CompressedSize < attempts[best].CompressedSize {
+				best = i
+			}
+		}
+		safeEmit(ctx, "compress:log", fmt.Sprintf("Warning: could not reach %s, smallest result is %s",
+			FormatFileSize(targetBytes), FormatFileSize(attempts[best].CompressedSize)))
+	}
+
+	result := attempts[best]
+	delete(attempts, best)
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Using %d dpi, JPEG quality %d: %s (saved %d%%)",
+		targetQualityLadder[best].resolution, targetQualityLadder[best].jpegQuality,
+		FormatFileSize(result.CompressedSize), result.SavingsPercent))
+
 	safeEmit(ctx, "compress:progress", ProgressUpdate{
 		Percent: 100,
 		Message: "Complete",
 	})
 
-	return &CompressionResult{
-		Success:        true,
-		OriginalSize:   originalSize,
-		CompressedSize: compressedSize,
-		SavingsPercent: savingsPercent,
-		OutputPath:     outputPath,
-	}, nil
+	return result, nil
+}
+
+// searchQualityLadder binary-searches ladder indexes 0..n-1 for the best quality (lowest index)
+// whose output fits targetBytes, assuming output size shrinks down the ladder.
+// try compresses at an index and returns the output size. Returns -1 if nothing fits.
+func searchQualityLadder(n int, targetBytes int64, try func(int) (int64, error)) (int, error) {
+	best := -1
+	lo, hi := 0, n-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		size, err := try(mid)
+		if err != nil {
+			return -1, err
+		}
+		if size <= targetBytes {
+			best = mid
+			hi = mid - 1
+		} else {
+			lo = mid + 1
+		}
+	}
+	return best, nil
+}
+
+// targetOptions builds the compression options for one CompressToTarget attempt
+func targetOptions(quality imageQuality) CompressionOptions {
+	return CompressionOptions{
+		ColorImageResolution: quality.resolution,
+		GrayImageResolution:  quality.resolution,
+		MonoImageResolution:  quality.resolution * 2, // line art needs more pixels
+		DownsampleThreshold:  1.0,
+		JPEGQuality:          quality.jpegQuality,
+		SubsetFonts:          true,
+	}
+}
+
+// jpegQFactor maps a JPEG quality (1-100) to Ghostscript's QFactor,
+// where 0.15 is near-lossless and larger values compress harder
+func jpegQFactor(quality int) float64 {
+	quality = max(1, min(100, quality))
+	return 0.1 + float64(100-quality)*0.024
 }
@@ -1,9 +1,13 @@
 package pdf
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
@@ -164,6 +168,386 @@ func TestCompressPDF_EmptyFile(t *testing.T) {
 	}
 }
 
+func TestCompressToTarget(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "high-res-images.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	info, _ := os.Stat(fixturePath)
+	target := info.Size() / 2
+
+	result, err := CompressToTarget(mockContext(), fixturePath, target)
+	if err != nil {
+		t.Fatalf("CompressToTarget() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+	if result.OriginalSize != info.Size() {
+		t.Errorf("CompressToTarget().OriginalSize = %d, want %d", result.OriginalSize, info.Size())
+	}
+}
+
+func TestCompressToTarget_InvalidTarget(t *testing.T) {
+	if _, err := CompressToTarget(mockContext(), "/nonexistent/file.pdf", 0); err == nil {
+		t.Error("CompressToTarget() should return error for zero target")
+	}
+	if _, err := CompressToTarget(mockContext(), "/nonexistent/file.pdf", 1024); err == nil {
+		t.Error("CompressToTarget() should return error for non-existent file")
+	}
+}
+
+func TestSearchQualityLadder(t *testing.T) {
+	// Output sizes per ladder step, shrinking with quality
+	sizes := []int64{900, 700, 500, 400, 300, 200, 150}
+
+	tests := []struct {
+		target int64
+		want   int
+	}{
+		{1000, 0},
+		{700, 1},
+		{450, 3},
+		{150, 6},
+		{100, -1},
+	}
+
+	for _, tt := range tests {
+		tried := map[int]bool{}
+		best, err := searchQualityLadder(len(sizes), tt.target, func(i int) (int64, error) {
+			if tried[i] {
+				t.Errorf("target %d: step %d tried twice", tt.target, i)
+			}
+			tried[i] = true
+			return sizes[i], nil
+		})
+		if err != nil {
+			t.Fatalf("searchQualityLadder() error = %v", err)
+		}
+		if best != tt.want {
+			t.Errorf("searchQualityLadder(target %d) = %d, want %d", tt.target, best, tt.want)
+		}
+		if len(tried) > 3 {
+			t.Errorf("searchQualityLadder(target %d) made %d attempts, want at most 3", tt.target, len(tried))
+		}
+		if tt.want == -1 && !tried[len(sizes)-1] {
+			t.Errorf("searchQualityLadder(target %d) never tried the quality floor", tt.target)
+		}
+	}
+}
+
+func TestGhostscriptArgs(t *testing.T) {
+	opts := CompressionOptions{
+		PDFSettings:          "ebook",
+		ColorImageResolution: 150,
+		MonoImageResolution:  300,
+		DownsampleThreshold:  1.5,
+		JPEGQuality:          80,
+		CompatibilityLevel:   "1.7",
+	}
+	args := ghostscriptArgs(opts, "in.pdf", "out.pdf")
+
+	for _, want := range []string{
+		"-dPDFSETTINGS=/ebook",
+		"-dCompatibilityLevel=1.7",
+		"-dSubsetFonts=false",
+		"-dDownsampleColorImages=true",
+		"-dColorImageResolution=150",
+		"-dColorImageDownsampleThreshold=1.50",
+		"-dMonoImageResolution=300",
+		"-dColorImageFilter=/DCTEncode",
+		"-sOutputFile=out.pdf",
+	} {
+		if !slices.Contains(args, want) {
+			t.Errorf("ghostscriptArgs() missing %q", want)
+		}
+	}
+	if slices.Contains(args, "-dDownsampleGrayImages=true") {
+		t.Error("ghostscriptArgs() should leave gray images to the base profile")
+	}
+	if args[len(args)-2] != "-f" || args[len(args)-1] != "in.pdf" {
+		t.Errorf("ghostscriptArgs() should end with the input file, got %v", args[len(args)-2:])
+	}
+
+	// Presets only set the base profile
+	args = ghostscriptArgs(presetOptions[PresetScreen], "in.pdf", "out.pdf")
+	if !slices.Contains(args, "-dPDFSETTINGS=/screen") || slices.Contains(args, "-c") {
+		t.Errorf("ghostscriptArgs(PresetScreen) = %v", args)
+	}
+	if !slices.Contains(args, "-dCompatibilityLevel=1.4") || !slices.Contains(args, "-dSubsetFonts=true") {
+		t.Errorf("ghostscriptArgs(PresetScreen) should target PDF 1.4 with subset fonts, got %v", args)
+	}
+	if args[len(args)-1] != "in.pdf" {
+		t.Errorf("ghostscriptArgs() should end with the input file, got %q", args[len(args)-1])
+	}
+
+	if jpegQFactor(90) >= jpegQFactor(40) {
+		t.Error("jpegQFactor() should grow as quality drops")
+	}
+}
+
+func TestScanGhostscriptPages(t *testing.T) {
+	output := `GPL Ghostscript 10.02.1 (2023-11-01)
+Copyright (C) 2023 Artifex Software, Inc.  All rights reserved.
+Processing pages 1 through 3.
+Page 1
+Page 2
+Loading font Helvetica from /usr/share/fonts/...
+Page 3
+`
+	var pages []int
+	scanGhostscriptPages(strings.NewReader(output), func(page int) {
+		pages = append(pages, page)
+	})
+
+	if fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("scanGhostscriptPages() pages = %v, want [1 2 3]", pages)
+	}
+}
+
+func TestOptionsForPreset(t *testing.T) {
+	opts, err := OptionsForPreset(PresetScreen)
+	if err != nil || opts.PDFSettings != "screen" {
+		t.Errorf("OptionsForPreset(screen) = %+v, %v", opts, err)
+	}
+	if opts, err := OptionsForPreset("unknown"); err != nil || opts != presetOptions[PresetDefault] {
+		t.Errorf("OptionsForPreset(unknown) = %+v, %v, want the default preset", opts, err)
+	}
+	// Lossless must not quietly turn into a Ghostscript rewrite
+	if _, err := OptionsForPreset(PresetLossless); err == nil {
+		t.Error("OptionsForPreset(lossless) should return error")
+	}
+}
+
+func TestCompressionOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    CompressionOptions
+		wantErr bool
+	}{
+		{"presets", presetOptions[PresetEbook], false},
+		{"zero value", CompressionOptions{}, false},
+		{"custom", CompressionOptions{ColorImageResolution: 120, DownsampleThreshold: 1.2, JPEGQuality: 75, CompatibilityLevel: "1.5"}, false},
+		{"unknown base", CompressionOptions{PDFSettings: "tiny"}, true},
+		{"resolution too low", CompressionOptions{GrayImageResolution: 5}, true},
+		{"resolution too high", CompressionOptions{MonoImageResolution: 5000}, true},
+		{"threshold below 1", CompressionOptions{DownsampleThreshold: 0.5}, true},
+		{"negative quality", CompressionOptions{JPEGQuality: -1}, true},
+		{"quality above 100", CompressionOptions{JPEGQuality: 101}, true},
+		{"unknown version", CompressionOptions{CompatibilityLevel: "1.8"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompressPDFWithOptions_InvalidOptions(t *testing.T) {
+	// Validation happens before the file or Ghostscript are touched
+	_, err := CompressPDFWithOptions(mockContext(), "/nonexistent/file.pdf", CompressionOptions{JPEGQuality: 200})
+	if err == nil || !strings.Contains(err.Error(), "invalid compression options") {
+		t.Errorf("CompressPDFWithOptions() error = %v, want invalid options error", err)
+	}
+}
+
+func TestCompressPDFWithOptions(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	opts := CompressionOptions{
+		ColorImageResolution: 100,
+		GrayImageResolution:  100,
+		JPEGQuality:          70,
+		SubsetFonts:          true,
+		StripMetadata:        true,
+	}
+	result, err := CompressPDFWithOptions(mockContext(), fixturePath, opts)
+	if err != nil {
+		t.Fatalf("CompressPDFWithOptions() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+}
+
+func TestStripMetadata(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	path := filepath.Join(t.TempDir(), "meta.pdf")
+	if err := api.AddPropertiesFile(fixturePath, path, map[string]string{"Title": "Secret title"}, nil); err != nil {
+		t.Fatalf("Failed to add properties: %v", err)
+	}
+
+	if err := stripMetadata(path); err != nil {
+		t.Fatalf("stripMetadata() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if bytes.Contains(data, []byte("Secret title")) {
+		t.Error("stripMetadata() left the title in the file")
+	}
+	if err := api.ValidateFile(path, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+}
+
+func TestCompressPDFWithOptions_SkipIfLarger(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	// prepress on a small text-only page usually grows the file
+	opts := presetOptions[PresetPrepress]
+	opts.SkipIfLarger = true
+	result, err := CompressPDFWithOptions(mockContext(), fixturePath, opts)
+	if err != nil {
+		t.Fatalf("CompressPDFWithOptions() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if result.CompressedSize > result.OriginalSize {
+		t.Errorf("CompressedSize = %d, want <= original %d", result.CompressedSize, result.OriginalSize)
+	}
+	if result.SavingsPercent < 0 {
+		t.Errorf("SavingsPercent = %d, want >= 0", result.SavingsPercent)
+	}
+}
+
+func TestFallbackCompression(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	info, err := os.Stat(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := fallbackCompression(fixturePath, info.Size())
+	if err != nil {
+		t.Fatalf("fallbackCompression() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	switch result.Method {
+	case MethodOptimize:
+		if result.CompressedSize >= info.Size() {
+			t.Errorf("optimized size %d is not smaller than original %d", result.CompressedSize, info.Size())
+		}
+	case MethodOriginal:
+		if result.CompressedSize != info.Size() {
+			t.Errorf("copied size %d, want original size %d", result.CompressedSize, info.Size())
+		}
+	default:
+		t.Errorf("Method = %q, want optimize or original", result.Method)
+	}
+	if result.OutputPath == fixturePath {
+		t.Error("fallbackCompression() returned the input path instead of a copy")
+	}
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+}
+
+func TestRankCompressionResults(t *testing.T) {
+	results := []CompressionResult{
+		{Preset: PresetPrinter, Success: true, CompressedSize: 300},
+		{Preset: PresetScreen, Error: "boom"},
+		{Preset: PresetEbook, Success: true, CompressedSize: 100},
+		{Preset: PresetPrepress, Success: true, CompressedSize: 200},
+	}
+	rankCompressionResults(results)
+
+	var got []CompressionPreset
+	for _, r := range results {
+		got = append(got, r.Preset)
+	}
+	want := []CompressionPreset{PresetEbook, PresetPrepress, PresetPrinter, PresetScreen}
+	if !slices.Equal(got, want) {
+		t.Errorf("rankCompressionResults() order = %v, want %v", got, want)
+	}
+}
+
+func TestCompressAllPresets(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	results, err := CompressAllPresets(mockContext(), fixturePath)
+	if err != nil {
+		t.Fatalf("CompressAllPresets() error = %v", err)
+	}
+	for _, r := range results {
+		defer CleanupTempFiles(r.OutputPath)
+	}
+
+	if len(results) != len(presetOrder) {
+		t.Fatalf("got %d results, want %d", len(results), len(presetOrder))
+	}
+	for i, r := range results {
+		if !r.Success {
+			t.Errorf("preset %s failed: %s", r.Preset, r.Error)
+			continue
+		}
+		if i > 0 && results[i-1].Success && r.CompressedSize < results[i-1].CompressedSize {
+			t.Errorf("results not ranked by size: %s (%d) after %s (%d)",
+				r.Preset, r.CompressedSize, results[i-1].Preset, results[i-1].CompressedSize)
+		}
+	}
+}
+
+func TestCompressAllPresets_WithoutGhostscript(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := GetGhostscriptPath(); err == nil {
+		t.Skip("Bundled Ghostscript found")
+	}
+
+	results, err := CompressAllPresets(mockContext(), fixturePath)
+	if err != nil {
+		t.Fatalf("CompressAllPresets() error = %v", err)
+	}
+	for _, r := range results {
+		defer CleanupTempFiles(r.OutputPath)
+	}
+
+	if len(results) != len(presetOrder) {
+		t.Fatalf("got %d results, want %d", len(results), len(presetOrder))
+	}
+	if !results[0].Success || results[0].Preset != PresetLossless {
+		t.Errorf("first result = %s (success %t), want a successful lossless result", results[0].Preset, results[0].Success)
+	}
+	for _, r := range results[1:] {
+		if r.Success || !strings.Contains(r.Error, "ghostscript not available") {
+			t.Errorf("preset %s: success %t, error %q, want a Ghostscript error", r.Preset, r.Success, r.Error)
+		}
+	}
+}
+
 func BenchmarkCompressPDF(b *testing.B) {
 	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
 	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
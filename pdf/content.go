@@ -0,0 +1,222 @@
+package pdf
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+)
+
+// matrix is a PDF transformation matrix [a b c d e f]
+type matrix [6]float64
+
+var identityMatrix = matrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns m × n, i.e. m applied first, then n
+func (m matrix) multiply(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// unitSize is the size in points that the unit square (an image) is drawn at
+func (m matrix) unitSize() (width, height float64) {
+	return math.Hypot(m[0], m[1]), math.Hypot(m[2], m[3])
+}
+
+// scanXObjectDraws calls draw for every XObject a content stream paints with Do,
+// with the transformation matrix in effect at that point
+func scanXObjectDraws(content []byte, ctm matrix, draw func(name string, ctm matrix)) {
+	var stack []matrix
+	var operands [][]byte
+
+	s := contentScanner{data: content}
+	for {
+		token, isOperator, ok := s.next()
+		if !ok {
+			return
+		}
+		if !isOperator {
+			operands = append(operands, token)
+			continue
+		}
+
+		switch string(token) {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if len(stack) > 0 {
+				ctm = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			if m, ok := parseMatrix(operands); ok {
+				ctm = m.multiply(ctm)
+			}
+		case "Do":
+			if len(operands) > 0 && bytes.HasPrefix(operands[len(operands)-1], []byte("/")) {
+				draw(string(operands[len(operands)-1][1:]), ctm)
+			}
+		case "BI":
+			s.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// parseMatrix reads the last six operands as a matrix
+func parseMatrix(operands [][]byte) (matrix, bool) {
+	var m matrix
+	if len(operands) < 6 {
+		return m, false
+	}
+	for i, operand := range operands[len(operands)-6:] {
+		v, err := strconv.ParseFloat(string(operand), 64)
+		if err != nil {
+			return m, false
+		}
+		m[i] = v
+	}
+	return m, true
+}
+
+// contentScanner splits a content stream into operands and operators. Strings, arrays and
+// dictionaries are skipped over as opaque operands; only numbers and names are kept intact.
+type contentScanner struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next token and whether it is an operator
+func (s *contentScanner) next() (token []byte, isOperator, ok bool) {
+	s.skipSpaceAndComments()
+	if s.pos >= len(s.data) {
+		return nil, false, false
+	}
+
+	start := s.pos
+	switch c := s.data[s.pos]; c {
+	case '(':
+		s.skipString()
+	case '<':
+		if s.pos+1 < len(s.data) && s.data[s.pos+1] == '<' {
+			s.pos += 2
+		} else {
+			s.skipPast('>')
+		}
+	case '>':
+		s.pos += 2
+	case '[', ']', '{', '}':
+		s.pos++
+	case '/':
+		s.pos++
+		s.skipRegular()
+	default:
+		s.skipRegular()
+		if s.pos == start {
+			// Stray delimiter such as ')'
+			s.pos++
+		}
+		token = s.data[start:min(s.pos, len(s.data))]
+		return token, !isOperand(token), true
+	}
+	return s.data[start:min(s.pos, len(s.data))], false, true
+}
+
+// skipInlineImage skips from after BI past the image data and its EI
+func (s *contentScanner) skipInlineImage() {
+	id := bytes.Index(s.data[s.pos:], []byte("ID"))
+	if id < 0 {
+		s.pos = len(s.data)
+		return
+	}
+	s.pos += id + 2
+
+	// The binary data can contain anything; EI must stand alone
+	for {
+		ei := bytes.Index(s.data[s.pos:], []byte("EI"))
+		if ei < 0 {
+			s.pos = len(s.data)
+			return
+		}
+		at := s.pos + ei
+		s.pos = at + 2
+		if isSpace(s.data[at-1]) && (s.pos == len(s.data) || isSpace(s.data[s.pos]) || isDelimiter(s.data[s.pos])) {
+			return
+		}
+	}
+}
+
+// skipString skips a literal string, which may contain balanced or escaped parentheses
+func (s *contentScanner) skipString() {
+	depth := 0
+	for ; s.pos < len(s.data); s.pos++ {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				s.pos++
+				return
+			}
+		}
+	}
+}
+
+func (s *contentScanner) skipPast(c byte) {
+	if i := bytes.IndexByte(s.data[s.pos:], c); i >= 0 {
+		s.pos += i + 1
+	} else {
+		s.pos = len(s.data)
+	}
+}
+
+func (s *contentScanner) skipRegular() {
+	for s.pos < len(s.data) && !isSpace(s.data[s.pos]) && !isDelimiter(s.data[s.pos]) {
+		s.pos++
+	}
+}
+
+func (s *contentScanner) skipSpaceAndComments() {
+	for s.pos < len(s.data) {
+		switch {
+		case isSpace(s.data[s.pos]):
+			s.pos++
+		case s.data[s.pos] == '%':
+			for s.pos < len(s.data) && s.data[s.pos] != '\n' && s.data[s.pos] != '\r' {
+				s.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// isOperand reports whether a regular token is a number or keyword value rather than an operator
+func isOperand(token []byte) bool {
+	switch string(token) {
+	case "true", "false", "null":
+		return true
+	}
+	_, err := strconv.ParseFloat(string(token), 64)
+	return err == nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
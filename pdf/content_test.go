@@ -0,0 +1,77 @@
+package pdf
+
+import (
+	"testing"
+)
+
+func TestScanXObjectDraws(t *testing.T) {
+	type draw struct {
+		name          string
+		width, height float64
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []draw
+	}{
+		{
+			name:    "single image",
+			content: "q 200 0 0 100 50 50 cm /Im1 Do Q",
+			want:    []draw{{"Im1", 200, 100}},
+		},
+		{
+			name:    "nested transforms and restore",
+			content: "q 2 0 0 2 0 0 cm q 100 0 0 50 0 0 cm /Im1 Do Q /Fm1 Do Q /Im2 Do",
+			want:    []draw{{"Im1", 200, 100}, {"Fm1", 2, 2}, {"Im2", 1, 1}},
+		},
+		{
+			name:    "strings and arrays are skipped",
+			content: "BT /F1 12 Tf (a \\) 1 0 0 1 cm (nested) Do) Tj [(x) -250 (y)] TJ ET q 10 0 0 10 0 0 cm /Im1 Do Q",
+			want:    []draw{{"Im1", 10, 10}},
+		},
+		{
+			name:    "inline image data is skipped",
+			content: "q BI /W 2 /H 2 /CS /G /BPC 8 ID \x00Do cm EIx\xff EI Q % comment /Im9 Do\nq 5 0 0 5 0 0 cm /Im1 Do Q",
+			want:    []draw{{"Im1", 5, 5}},
+		},
+		{
+			name:    "unbalanced Q is ignored",
+			content: "Q Q 3 0 0 4 0 0 cm /Im1 Do",
+			want:    []draw{{"Im1", 3, 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []draw
+			scanXObjectDraws([]byte(tt.content), identityMatrix, func(name string, ctm matrix) {
+				w, h := ctm.unitSize()
+				got = append(got, draw{name, w, h})
+			})
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d draws %v, want %v", len(got), got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("draw %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatrixMultiply(t *testing.T) {
+	scale := matrix{2, 0, 0, 3, 0, 0}
+	translate := matrix{1, 0, 0, 1, 10, 20}
+
+	// Scale first, then translate
+	if got, want := scale.multiply(translate), (matrix{2, 0, 0, 3, 10, 20}); got != want {
+		t.Errorf("scale × translate = %v, want %v", got, want)
+	}
+	// Translate first, then scale the translation too
+	if got, want := translate.multiply(scale), (matrix{2, 0, 0, 3, 20, 60}); got != want {
+		t.Errorf("translate × scale = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// compressLossless implements PresetLossless: pdfcpu's optimizer rewrites the file without
+// re-rendering anything, so forms, links and tagged structure survive unchanged
+func compressLossless(ctx context.Context, inputPath string) (*CompressionResult, error) {
+	originalInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+	originalSize := originalInfo.Size()
+	if originalSize == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 10,
+		Message: "Preparing optimization...",
+	})
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Input file: %s (%s)", originalInfo.Name(), FormatFileSize(originalSize)))
+	safeEmit(ctx, "compress:log", "Using preset: Lossless optimization (no quality loss)")
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 30,
+		Message: "Optimizing document structure...",
+	})
+
+	result, err := optimizeLossless(inputPath, originalSize)
+	if err != nil {
+		return nil, err
+	}
+	result.Preset = PresetLossless
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 90,
+		Message: "Finalizing...",
+	})
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Optimized: %s", result.Optimization.summary()))
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Original: %s, Optimized: %s", FormatFileSize(originalSize), FormatFileSize(result.CompressedSize)))
+	if result.SavingsPercent < 0 {
+		safeEmit(ctx, "compress:log", "Warning: Optimized file is larger than original")
+	} else {
+		safeEmit(ctx, "compress:log", fmt.Sprintf("Saved: %d%%", result.SavingsPercent))
+	}
+
+	safeEmit(ctx, "compress:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return result, nil
+}
+
+// optimizeLossless writes an optimized copy of inputPath to a new temp file: duplicate fonts,
+// images and content streams are shared, unused resources and objects are dropped, and
+// uncompressed streams are Flate-compressed
+func optimizeLossless(inputPath string, originalSize int64) (*CompressionResult, error) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer f.Close()
+
+	conf := model.NewDefaultConfiguration()
+	conf.Cmd = model.OPTIMIZE
+	conf.OptimizeDuplicateContentStreams = true
+
+	pdfCtx, err := api.ReadValidateAndOptimize(f, conf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot optimize PDF: %w", err)
+	}
+
+	report := &OptimizationReport{
+		DuplicateFonts:  len(pdfCtx.Optimize.DuplicateFonts),
+		DuplicateImages: len(pdfCtx.Optimize.DuplicateImages),
+	}
+	objectsBefore := countObjects(pdfCtx)
+
+	if report.CompressedStreams, err = compressUnfilteredStreams(pdfCtx); err != nil {
+		return nil, fmt.Errorf("cannot compress streams: %w", err)
+	}
+
+	outputPath, err := CreateTempFile("optimized", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+	if err := api.WriteContextFile(pdfCtx, outputPath); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot write PDF: %w", err)
+	}
+
+	// pdfcpu only writes objects reachable from the document, so the difference is what was dropped
+	if written, err := api.ReadContextFile(outputPath); err == nil {
+		report.RemovedObjects = max(0, objectsBefore-countObjects(written))
+	}
+
+	result, err := newCompressionResult(originalSize, outputPath, MethodOptimize)
+	if err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, err
+	}
+	result.Optimization = report
+	return result, nil
+}
+
+// compressUnfilteredStreams Flate-compresses streams stored without any filter,
+// keeping the original wherever compression doesn't help. Returns the number compressed.
+func compressUnfilteredStreams(pdfCtx *model.Context) (int, error) {
+	compressed := 0
+	for _, entry := range pdfCtx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || len(sd.FilterPipeline) > 0 || len(sd.Raw) == 0 {
+			continue
+		}
+		// XMP metadata is left readable, as the spec recommends
+		if t := sd.Type(); t != nil && (*t == "Metadata" || *t == "XRef" || *t == "ObjStm") {
+			continue
+		}
+
+		// Encode rewrites /Length, so work on a copy of the dict in case the stream is kept as is
+		raw := sd.Raw
+		sd.Dict = sd.Dict.Clone().(types.Dict)
+		sd.Content = raw
+		sd.FilterPipeline = []types.PDFFilter{{Name: filter.Flate}}
+		if err := sd.Encode(); err != nil {
+			return compressed, err
+		}
+		if len(sd.Raw) >= len(raw) {
+			continue
+		}
+
+		sd.Insert("Filter", types.Name(filter.Flate))
+		entry.Object = sd
+		compressed++
+	}
+	return compressed, nil
+}
+
+// countObjects counts the objects in use, not counting the cross-reference and object
+// streams that only package them
+func countObjects(pdfCtx *model.Context) int {
+	count := 0
+	for objNr, entry := range pdfCtx.Table {
+		if objNr == 0 || entry == nil || entry.Free || entry.Object == nil {
+			continue
+		}
+		if sd, ok := entry.Object.(types.StreamDict); ok {
+			if t := sd.Type(); t != nil && (*t == "XRef" || *t == "ObjStm") {
+				continue
+			}
+		}
+		count++
+	}
+	return count
+}
+
+// summary describes what the optimizer did for the log
+func (r *OptimizationReport) summary() string {
+	var parts []string
+	if r.DuplicateFonts > 0 {
+		parts = append(parts, fmt.Sprintf("removed %d duplicate fonts", r.DuplicateFonts))
+	}
+	if r.DuplicateImages > 0 {
+		parts = append(parts, fmt.Sprintf("removed %d duplicate images", r.DuplicateImages))
+	}
+	if r.RemovedObjects > 0 {
+		parts = append(parts, fmt.Sprintf("dropped %d objects", r.RemovedObjects))
+	}
+	if r.CompressedStreams > 0 {
+		parts = append(parts, fmt.Sprintf("compressed %d streams", r.CompressedStreams))
+	}
+	if len(parts) == 0 {
+		return "nothing to remove, the file is already optimized"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,184 @@
+package pdf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestCompressPDF_Lossless(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	// No Ghostscript needed
+	result, err := CompressPDF(mockContext(), fixturePath, PresetLossless)
+	if err != nil {
+		t.Fatalf("CompressPDF(lossless) error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if result.Method != MethodOptimize {
+		t.Errorf("Method = %q, want %q", result.Method, MethodOptimize)
+	}
+	if result.Preset != PresetLossless {
+		t.Errorf("Preset = %q, want %q", result.Preset, PresetLossless)
+	}
+	if result.Optimization == nil {
+		t.Fatal("Optimization report is missing")
+	}
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+
+	original, _ := api.PageCountFile(fixturePath)
+	optimized, err := api.PageCountFile(result.OutputPath)
+	if err != nil || optimized != original {
+		t.Errorf("page count = %d (err %v), want %d", optimized, err, original)
+	}
+}
+
+func TestOptimizeLossless_CompressesStreams(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	uncompressedPath := writeUncompressedCopy(t, fixturePath)
+	info, err := os.Stat(uncompressedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := optimizeLossless(uncompressedPath, info.Size())
+	if err != nil {
+		t.Fatalf("optimizeLossless() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if result.Optimization.CompressedStreams == 0 {
+		t.Error("CompressedStreams = 0, want uncompressed streams to be compressed")
+	}
+	if result.CompressedSize >= info.Size() {
+		t.Errorf("CompressedSize = %d, want less than %d", result.CompressedSize, info.Size())
+	}
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+}
+
+func TestOptimizeLossless_StreamLengths(t *testing.T) {
+	for _, name := range []string{"simple-1page.pdf", "multi-page.pdf"} {
+		t.Run(name, func(t *testing.T) {
+			fixturePath := filepath.Join("..", "test", "fixtures", "valid", name)
+			if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+				t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+			}
+
+			// Small streams that Flate can't shrink stay uncompressed
+			uncompressedPath := writeUncompressedCopy(t, fixturePath)
+			info, err := os.Stat(uncompressedPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := optimizeLossless(uncompressedPath, info.Size())
+			if err != nil {
+				t.Fatalf("optimizeLossless() error = %v", err)
+			}
+			defer CleanupTempFiles(result.OutputPath)
+
+			data, err := os.ReadFile(result.OutputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Every direct /Length must match the bytes between "stream" and "endstream"
+			streams := 0
+			for _, m := range streamPattern.FindAllSubmatchIndex(data, -1) {
+				if m[4] >= 0 {
+					continue // indirect length
+				}
+				declared, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+				end := bytes.Index(data[m[1]:], []byte("endstream"))
+				if end < 0 {
+					t.Fatalf("stream at offset %d has no endstream", m[1])
+				}
+				actual := bytes.TrimSuffix(bytes.TrimSuffix(data[m[1]:m[1]+end], []byte("\n")), []byte("\r"))
+				if declared != len(actual) {
+					t.Errorf("stream at offset %d declares /Length %d, has %d bytes", m[1], declared, len(actual))
+				}
+				streams++
+			}
+			if streams == 0 {
+				t.Error("no streams found in output")
+			}
+		})
+	}
+}
+
+// writeUncompressedCopy stores every Flate stream of a fixture uncompressed, as some
+// PDF generators do, and returns the path of the copy
+func writeUncompressedCopy(t *testing.T, fixturePath string) string {
+	t.Helper()
+	pdfCtx, err := api.ReadContextFile(fixturePath)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	decompressed := 0
+	for _, entry := range pdfCtx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || len(sd.FilterPipeline) != 1 || sd.FilterPipeline[0].Name != "FlateDecode" || sd.FilterPipeline[0].DecodeParms != nil {
+			continue
+		}
+		if err := sd.Decode(); err != nil {
+			continue
+		}
+		sd.FilterPipeline = nil
+		sd.Delete("Filter")
+		if err := sd.Encode(); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		entry.Object = sd
+		decompressed++
+	}
+	if decompressed == 0 {
+		t.Skip("Fixture has no Flate streams")
+	}
+
+	uncompressedPath := filepath.Join(t.TempDir(), "uncompressed.pdf")
+	pdfCtx.Conf.WriteObjectStream = false
+	if err := api.WriteContextFile(pdfCtx, uncompressedPath); err != nil {
+		t.Fatalf("Failed to write uncompressed PDF: %v", err)
+	}
+	return uncompressedPath
+}
+
+// streamPattern matches an object's stream dictionary up to the start of its data,
+// capturing the /Length value and whether it is an indirect reference
+var streamPattern = regexp.MustCompile(`(?s)\d+ 0 obj\s*<<(?:[^s]|s[^t])*?/Length\s*(\d+)(\s+\d+\s+R)?(?:[^s]|s[^t])*?>>\s*stream\r?\n`)
+
+func TestOptimizationReport_Summary(t *testing.T) {
+	tests := []struct {
+		report OptimizationReport
+		want   string
+	}{
+		{OptimizationReport{}, "nothing to remove, the file is already optimized"},
+		{OptimizationReport{DuplicateFonts: 2, CompressedStreams: 3}, "removed 2 duplicate fonts, compressed 3 streams"},
+		{OptimizationReport{DuplicateImages: 1, RemovedObjects: 7}, "removed 1 duplicate images, dropped 7 objects"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.report.summary(); got != tt.want {
+			t.Errorf("summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
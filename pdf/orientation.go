@@ -0,0 +1,283 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const (
+	// orientationDPI is high enough to resolve ascenders on body text while keeping pages small
+	orientationDPI = 72
+	// minOrientationConfidence is the confidence below which no rotation is suggested
+	minOrientationConfidence = 0.2
+	// minTextLines is the fewest text lines needed before the page is judged at all
+	minTextLines = 3
+)
+
+// DetectOrientation renders each page at low resolution and proposes the rotation
+// that would make its text upright. Analysis runs entirely in Go on the rendered bitmaps.
+// Every page gets a suggestion, Rotate0 for pages that already read upright, so the
+// whole list can be passed to ApplyRotations and upright pages are left as they are.
+func DetectOrientation(ctx context.Context, path string) ([]OrientationSuggestion, error) {
+	pageCount, err := getPageCount(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+
+	gsPath, err := GetGhostscriptPath()
+	if err != nil {
+		return nil, fmt.Errorf("ghostscript not available: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "dadjoke_orientation")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	safeEmit(ctx, "rotate:progress", ProgressUpdate{Percent: 0, Message: "Rendering pages..."})
+	safeEmit(ctx, "rotate:log", fmt.Sprintf("Analyzing orientation of %d pages", pageCount))
+
+	// Render all pages in one Ghostscript call
+	outPattern := filepath.Join(tmpDir, "page_%03d.png")
+	args := []string{
+		"-dSAFER",
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-sDEVICE=pnggray",
+		fmt.Sprintf("-r%d", orientationDPI),
+		fmt.Sprintf("-sOutputFile=%s", outPattern),
+		path,
+	}
+
+	cmd := exec.CommandContext(ctx, gsPath, args...)
+	hideWindow(cmd) // Hide console window on Windows
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := stderr.String()
+		if errMsg != "" {
+			return nil, fmt.Errorf("ghostscript failed: %s", errMsg)
+		}
+		return nil, fmt.Errorf("ghostscript failed: %w", err)
+	}
+
+	suggestions := make([]OrientationSuggestion, 0, pageCount)
+	for i := 1; i <= pageCount; i++ {
+		data, err := os.ReadFile(filepath.Join(tmpDir, fmt.Sprintf("page_%03d.png", i)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read rendered page %d: %w", i, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode rendered page %d: %w", i, err)
+		}
+
+		rotation, confidence := detectPageOrientation(img)
+		suggestions = append(suggestions, OrientationSuggestion{
+			PageNum:    i,
+			Rotation:   rotation,
+			Confidence: confidence,
+		})
+
+		if rotation != Rotate0 {
+			safeEmit(ctx, "rotate:log", fmt.Sprintf("Page %d looks rotated, suggest %d° (confidence %.0f%%)", i, rotation, confidence*100))
+		}
+		safeEmit(ctx, "rotate:progress", ProgressUpdate{
+			Percent: i * 100 / pageCount,
+			Message: fmt.Sprintf("Analyzed page %d/%d", i, pageCount),
+		})
+	}
+
+	return suggestions, nil
+}
+
+// detectPageOrientation returns the clockwise rotation that makes the page's text upright
+func detectPageOrientation(img image.Image) (RotationAngle, float64) {
+	m := newInkMap(img)
+
+	// Horizontal text leaves empty rows between lines but few empty columns
+	rowGaps := profileGapRatio(m.rowProfile())
+	colGaps := profileGapRatio(m.colProfile())
+	if rowGaps == 0 && colGaps == 0 {
+		return Rotate0, 0
+	}
+	axisConfidence := math.Abs(rowGaps-colGaps) / math.Max(rowGaps, colGaps)
+
+	sideways := colGaps > rowGaps
+	if sideways {
+		// Turn the lines horizontal; the result reads either upright or upside down
+		m = m.rotate90()
+	}
+
+	bias, lines := ascenderBias(m)
+	if lines < minTextLines {
+		return Rotate0, 0
+	}
+
+	confidence := math.Min(1, math.Abs(bias)*2) * math.Min(1, axisConfidence*2)
+	if confidence < minOrientationConfidence {
+		return Rotate0, confidence
+	}
+
+	switch {
+	case sideways && bias > 0:
+		return Rotate90, confidence
+	case sideways:
+		return Rotate270, confidence
+	case bias < 0:
+		return Rotate180, confidence
+	default:
+		return Rotate0, confidence
+	}
+}
+
+// inkMap is a binarized page image where true marks a dark pixel
+type inkMap struct {
+	width, height int
+	ink           []bool
+}
+
+// newInkMap thresholds an image into ink and background
+func newInkMap(img image.Image) inkMap {
+	b := img.Bounds()
+	m := inkMap{width: b.Dx(), height: b.Dy(), ink: make([]bool, b.Dx()*b.Dy())}
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			gray := color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray)
+			m.ink[y*m.width+x] = gray.Y < 128
+		}
+	}
+	return m
+}
+
+// rotate90 returns the map rotated 90 degrees clockwise
+func (m inkMap) rotate90() inkMap {
+	r := inkMap{width: m.height, height: m.width, ink: make([]bool, len(m.ink))}
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			r.ink[x*r.width+(m.height-1-y)] = m.ink[y*m.width+x]
+		}
+	}
+	return r
+}
+
+// rowProfile counts ink pixels in each row
+func (m inkMap) rowProfile() []int {
+	profile := make([]int, m.height)
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			if m.ink[y*m.width+x] {
+				profile[y]++
+			}
+		}
+	}
+	return profile
+}
+
+// colProfile counts ink pixels in each column
+func (m inkMap) colProfile() []int {
+	profile := make([]int, m.width)
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			if m.ink[y*m.width+x] {
+				profile[x]++
+			}
+		}
+	}
+	return profile
+}
+
+// profileGapRatio returns the fraction of near-empty bins between the first
+// and last inked bin. Gaps between text lines make this high across lines,
+// while word gaps rarely line up to leave a column empty along them.
+func profileGapRatio(profile []int) float64 {
+	first, last, peak := -1, -1, 0
+	for i, v := range profile {
+		if v > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+		peak = max(peak, v)
+	}
+	if first < 0 || last == first {
+		return 0
+	}
+
+	threshold := peak / 20
+	gaps := 0
+	for _, v := range profile[first : last+1] {
+		if v <= threshold {
+			gaps++
+		}
+	}
+	return float64(gaps) / float64(last-first+1)
+}
+
+// ascenderBias compares ink above and below the x-height band of each text line.
+// Latin text has far more ascenders than descenders, so the result is positive
+// for upright text and negative for upside-down text. It also returns the
+// number of text lines found.
+func ascenderBias(m inkMap) (float64, int) {
+	rows := m.rowProfile()
+
+	maxRow := 0
+	for _, v := range rows {
+		maxRow = max(maxRow, v)
+	}
+	if maxRow == 0 {
+		return 0, 0
+	}
+	// Ignore stray specks when finding the gaps between lines
+	threshold := maxRow / 20
+
+	var above, below float64
+	lines := 0
+	for y := 0; y < len(rows); {
+		if rows[y] <= threshold {
+			y++
+			continue
+		}
+
+		// Find the extent of this line and its densest row
+		start, peak := y, 0
+		for y < len(rows) && rows[y] > threshold {
+			peak = max(peak, rows[y])
+			y++
+		}
+		end := y
+
+		// The x-height band is where the line is at least half as dense as its peak
+		coreStart, coreEnd := start, end-1
+		for rows[coreStart]*2 < peak {
+			coreStart++
+		}
+		for rows[coreEnd]*2 < peak {
+			coreEnd--
+		}
+
+		for i := start; i < coreStart; i++ {
+			above += float64(rows[i])
+		}
+		for i := coreEnd + 1; i < end; i++ {
+			below += float64(rows[i])
+		}
+		lines++
+	}
+
+	if above+below == 0 {
+		return 0, lines
+	}
+	return (above - below) / (above + below), lines
+}
@@ -0,0 +1,190 @@
+package pdf
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// syntheticTextPage draws rows of glyph-like blocks with more ascenders than
+// descenders, like upright Latin text
+func syntheticTextPage() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 300, 400))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	ink := func(x0, y0, x1, y1 int) {
+		draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	}
+
+	for line := 0; line < 15; line++ {
+		baseline := 40 + line*20
+		x := 20 + (line*7)%11
+		for letter := 0; x < 270; letter++ {
+			// Word gaps land at different columns on each line
+			if (letter+line)%6 == 5 {
+				x += 5
+				continue
+			}
+			ink(x, baseline-6, x+4, baseline) // x-height body
+			if (letter+line)%3 == 0 {
+				ink(x, baseline-10, x+1, baseline-6) // ascender
+			}
+			if (letter+line)%9 == 4 {
+				ink(x+3, baseline, x+4, baseline+3) // descender
+			}
+			x += 6
+		}
+	}
+
+	return img
+}
+
+func TestDetectPageOrientation(t *testing.T) {
+	upright := syntheticTextPage()
+
+	// A page turned clockwise by `turned` needs the opposite rotation to fix it
+	tests := []struct {
+		turned   int
+		expected RotationAngle
+	}{
+		{0, Rotate0},
+		{90, Rotate270},
+		{180, Rotate180},
+		{270, Rotate90},
+	}
+
+	for _, tt := range tests {
+		var page image.Image = upright
+		if tt.turned != 0 {
+			page = rotateImage(upright, tt.turned)
+		}
+
+		rotation, confidence := detectPageOrientation(page)
+		if rotation != tt.expected {
+			t.Errorf("Page turned %d°: suggested %d°, want %d°", tt.turned, rotation, tt.expected)
+		}
+		if confidence < minOrientationConfidence {
+			t.Errorf("Page turned %d°: confidence %.2f below threshold", tt.turned, confidence)
+		}
+	}
+}
+
+func TestDetectPageOrientation_BlankPage(t *testing.T) {
+	blank := image.NewGray(image.Rect(0, 0, 300, 400))
+	draw.Draw(blank, blank.Bounds(), image.White, image.Point{}, draw.Src)
+
+	rotation, confidence := detectPageOrientation(blank)
+	if rotation != Rotate0 || confidence != 0 {
+		t.Errorf("Blank page: got %d° at %.2f, want 0° at 0", rotation, confidence)
+	}
+}
+
+func TestInkMapRotate90(t *testing.T) {
+	// 2x1 map with ink on the left
+	m := inkMap{width: 2, height: 1, ink: []bool{true, false}}
+
+	r := m.rotate90()
+	if r.width != 1 || r.height != 2 {
+		t.Fatalf("rotate90() size = %dx%d, want 1x2", r.width, r.height)
+	}
+	// Clockwise: the left column becomes the top row
+	if !r.ink[0] || r.ink[1] {
+		t.Errorf("rotate90() ink = %v, want [true false]", r.ink)
+	}
+}
+
+func TestDetectOrientation(t *testing.T) {
+	ctx := context.Background()
+
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Test fixture not found")
+	}
+
+	doc, err := GetPDFInfo(fixturePath)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+
+	suggestions, err := DetectOrientation(ctx, fixturePath)
+	if err != nil {
+		t.Fatalf("DetectOrientation failed: %v", err)
+	}
+
+	if len(suggestions) != doc.PageCount {
+		t.Fatalf("Expected %d suggestions, got %d", doc.PageCount, len(suggestions))
+	}
+
+	for i, s := range suggestions {
+		if s.PageNum != i+1 {
+			t.Errorf("Suggestion %d: expected PageNum %d, got %d", i, i+1, s.PageNum)
+		}
+		switch s.Rotation {
+		case Rotate0, Rotate90, Rotate180, Rotate270:
+		default:
+			t.Errorf("Page %d: invalid rotation %d", s.PageNum, s.Rotation)
+		}
+		if s.Confidence < 0 || s.Confidence > 1 {
+			t.Errorf("Page %d: confidence %.2f out of range", s.PageNum, s.Confidence)
+		}
+	}
+
+	// The full list, upright pages included, is accepted by ApplyRotations
+	result, err := ApplyRotations(mockContext(), fixturePath, suggestedRotations(suggestions))
+	if err != nil {
+		t.Fatalf("ApplyRotations() with all suggestions error = %v", err)
+	}
+	CleanupTempFiles(result.OutputPath)
+}
+
+func TestApplyRotations_UprightSuggestions(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Test fixture not found")
+	}
+
+	doc, err := GetPDFInfo(fixturePath)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+	if doc.PageCount < 2 {
+		t.Skip("Need at least 2 pages for this test")
+	}
+
+	// What DetectOrientation returns for a document with one sideways page
+	suggestions := make([]OrientationSuggestion, doc.PageCount)
+	for i := range suggestions {
+		suggestions[i] = OrientationSuggestion{PageNum: i + 1, Rotation: Rotate0}
+	}
+	suggestions[1] = OrientationSuggestion{PageNum: 2, Rotation: Rotate90, Confidence: 0.8}
+
+	result, err := ApplyRotations(mockContext(), fixturePath, suggestedRotations(suggestions))
+	if err != nil {
+		t.Fatalf("ApplyRotations() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if result.PagesRotated != 1 {
+		t.Errorf("ApplyRotations().PagesRotated = %d, want 1", result.PagesRotated)
+	}
+}
+
+// suggestedRotations converts suggestions the way the frontend passes them on
+func suggestedRotations(suggestions []OrientationSuggestion) []PageRotation {
+	rotations := make([]PageRotation, len(suggestions))
+	for i, s := range suggestions {
+		rotations[i] = PageRotation{PageNum: s.PageNum, Rotation: s.Rotation}
+	}
+	return rotations
+}
+
+func TestDetectOrientation_InvalidFile(t *testing.T) {
+	_, err := DetectOrientation(context.Background(), "/nonexistent/file.pdf")
+	if err == nil {
+		t.Error("Expected error for nonexistent file")
+	}
+}
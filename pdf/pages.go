@@ -0,0 +1,240 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+var (
+	// ErrNoPagesSelected is returned when a page operation is given an empty page list
+	ErrNoPagesSelected = errors.New("no pages selected")
+
+	// ErrPageOutOfRange is returned when a page number is outside 1..PageCount
+	ErrPageOutOfRange = errors.New("page out of range")
+
+	// ErrAllPagesDeleted is returned when a deletion would leave a document without pages
+	ErrAllPagesDeleted = errors.New("cannot delete all pages")
+)
+
+// DeletePages creates a new PDF without the specified pages (1-indexed)
+func DeletePages(ctx context.Context, path string, pages []int) (*PDFDocument, error) {
+	doc, err := GetPDFInfo(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePageNumbers(pages, doc.PageCount); err != nil {
+		return nil, err
+	}
+
+	deleted := make(map[int]bool, len(pages))
+	for _, pageNum := range pages {
+		deleted[pageNum] = true
+	}
+	if len(deleted) == doc.PageCount {
+		return nil, ErrAllPagesDeleted
+	}
+
+	var remaining []int
+	for pageNum := 1; pageNum <= doc.PageCount; pageNum++ {
+		if !deleted[pageNum] {
+			remaining = append(remaining, pageNum)
+		}
+	}
+
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Deleting %d of %d pages", len(deleted), doc.PageCount))
+
+	return collectPages(path, "edited", remaining)
+}
+
+// DuplicatePages creates a new PDF where each specified page (1-indexed) is followed by a copy of itself.
+// A page listed more than once gets one copy per listing.
+func DuplicatePages(ctx context.Context, path string, pages []int) (*PDFDocument, error) {
+	doc, err := GetPDFInfo(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePageNumbers(pages, doc.PageCount); err != nil {
+		return nil, err
+	}
+
+	copies := make(map[int]int, len(pages))
+	for _, pageNum := range pages {
+		copies[pageNum]++
+	}
+
+	var pageOrder []int
+	for pageNum := 1; pageNum <= doc.PageCount; pageNum++ {
+		for n := 0; n <= copies[pageNum]; n++ {
+			pageOrder = append(pageOrder, pageNum)
+		}
+	}
+
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Duplicating %d pages", len(pages)))
+
+	return collectPages(path, "edited", pageOrder)
+}
+
+// ReversePages creates a new PDF with the page order reversed, e.g. to fix a scan stack fed upside down
+func ReversePages(ctx context.Context, path string) (*PDFDocument, error) {
+	return collectSelection(ctx, path, "1-l", true)
+}
+
+// ExtractOdd creates a new PDF with only the odd pages (1, 3, 5, ...)
+func ExtractOdd(ctx context.Context, path string) (*PDFDocument, error) {
+	return collectSelection(ctx, path, "odd", false)
+}
+
+// ExtractEven creates a new PDF with only the even pages (2, 4, 6, ...)
+func ExtractEven(ctx context.Context, path string) (*PDFDocument, error) {
+	return collectSelection(ctx, path, "even", false)
+}
+
+// collectSelection writes the pages matched by a page selection expression to a new PDF,
+// optionally in reverse order
+func collectSelection(ctx context.Context, path, expr string, reverse bool) (*PDFDocument, error) {
+	doc, err := GetPDFInfo(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := ParsePageSelection(expr, doc.PageCount)
+	if err != nil {
+		return nil, err
+	}
+	if reverse {
+		slices.Reverse(pages)
+	}
+
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Selecting %d of %d pages (%s)", len(pages), doc.PageCount, expr))
+
+	return collectPages(path, "selected", pages)
+}
+
+// InsertBlankPages creates a new PDF with blank pages added after each page in positions
+// (0 inserts before the first page), plus any pages requested by opts.
+// Each blank page takes the media box of the page it follows (or precedes, at position 0).
+func InsertBlankPages(ctx context.Context, path string, positions []int, opts BlankPageOptions) (*PDFDocument, error) {
+	if len(positions) == 0 && !opts.AfterEveryPage && opts.PadToMultiple == 0 {
+		return nil, ErrNoPagesSelected
+	}
+	if opts.PadToMultiple < 0 {
+		return nil, fmt.Errorf("invalid page multiple: %d", opts.PadToMultiple)
+	}
+
+	doc, err := GetPDFInfo(path)
+	if err != nil {
+		return nil, err
+	}
+
+	atFront := false
+	after := types.IntSet{}
+	for _, pos := range positions {
+		if pos < 0 || pos > doc.PageCount {
+			return nil, fmt.Errorf("%w: cannot insert after page %d (document has %d pages)", ErrPageOutOfRange, pos, doc.PageCount)
+		}
+		if pos == 0 {
+			atFront = true
+		} else {
+			after[pos] = true
+		}
+	}
+	if opts.AfterEveryPage {
+		for pageNum := 1; pageNum <= doc.PageCount; pageNum++ {
+			after[pageNum] = true
+		}
+	}
+
+	pdfCtx, err := readPDFContext(path, model.INSERTPAGESAFTER)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+
+	// Read page sizes up front; pdfcpu's page count is stale once pages are inserted
+	dims, err := pdfCtx.PageDims()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read page sizes: %w", err)
+	}
+
+	pageCount := doc.PageCount
+	if err := insertBlankPagesAfter(pdfCtx, dims, after); err != nil {
+		return nil, fmt.Errorf("cannot insert blank pages: %w", err)
+	}
+	pageCount += len(after)
+
+	if atFront {
+		if err := pdfCtx.InsertBlankPages(types.IntSet{1: true}, &dims[0], true); err != nil {
+			return nil, fmt.Errorf("cannot insert blank pages: %w", err)
+		}
+		pageCount++
+	}
+	if opts.PadToMultiple > 0 {
+		last := dims[len(dims)-1]
+		for pageCount%opts.PadToMultiple != 0 {
+			if err := pdfCtx.InsertBlankPages(types.IntSet{pageCount: true}, &last, false); err != nil {
+				return nil, fmt.Errorf("cannot insert blank pages: %w", err)
+			}
+			pageCount++
+		}
+	}
+
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Inserted %d blank pages", pageCount-doc.PageCount))
+
+	outputPath, err := CreateTempFile("blank_pages", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	if err := api.WriteContextFile(pdfCtx, outputPath); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot write PDF: %w", err)
+	}
+
+	return GetPDFInfo(outputPath)
+}
+
+// validatePageNumbers checks that pages is non-empty and every page is within 1..pageCount
+func validatePageNumbers(pages []int, pageCount int) error {
+	if len(pages) == 0 {
+		return ErrNoPagesSelected
+	}
+
+	var invalid []int
+	for _, pageNum := range pages {
+		if pageNum < 1 || pageNum > pageCount {
+			invalid = append(invalid, pageNum)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Ints(invalid)
+		return fmt.Errorf("%w: %v (document has %d pages)", ErrPageOutOfRange, invalid, pageCount)
+	}
+	return nil
+}
+
+// collectPages writes the given pages of path, in order, to a new temp file
+func collectPages(path, prefix string, pageOrder []int) (*PDFDocument, error) {
+	outputPath, err := CreateTempFile(prefix, ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	// Build page selection string for pdfcpu
+	pageSelections := make([]string, len(pageOrder))
+	for i, pageNum := range pageOrder {
+		pageSelections[i] = fmt.Sprintf("%d", pageNum)
+	}
+
+	if err := api.CollectFile(path, outputPath, pageSelections, nil); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot write pages: %w", err)
+	}
+
+	return GetPDFInfo(outputPath)
+}
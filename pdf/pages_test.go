@@ -0,0 +1,327 @@
+package pdf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestDeletePages(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, _ := api.PageCountFile(fixture)
+	if originalPages < 3 {
+		t.Skip("Need at least 3 pages for this test")
+	}
+
+	tests := []struct {
+		name      string
+		pages     []int
+		wantPages int
+	}{
+		{"single page", []int{1}, originalPages - 1},
+		{"several pages", []int{originalPages, 2}, originalPages - 2},
+		{"repeated page", []int{2, 2}, originalPages - 1},
+		{"all but one", pageSequence(2, originalPages), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DeletePages(mockContext(), fixture, tt.pages)
+			if err != nil {
+				t.Fatalf("DeletePages() error = %v", err)
+			}
+			defer CleanupTempFiles(result.Path)
+
+			if err := api.ValidateFile(result.Path, nil); err != nil {
+				t.Errorf("Output is not valid PDF: %v", err)
+			}
+			if result.PageCount != tt.wantPages {
+				t.Errorf("DeletePages() page count = %d, want %d", result.PageCount, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestDeletePages_Errors(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, _ := api.PageCountFile(fixture)
+
+	tests := []struct {
+		name    string
+		pages   []int
+		wantErr error
+	}{
+		{"all pages", pageSequence(1, originalPages), ErrAllPagesDeleted},
+		{"all pages with repeats", append(pageSequence(1, originalPages), 1), ErrAllPagesDeleted},
+		{"empty list", nil, ErrNoPagesSelected},
+		{"page zero", []int{0}, ErrPageOutOfRange},
+		{"past the end", []int{1, originalPages + 1}, ErrPageOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DeletePages(mockContext(), fixture, tt.pages)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeletePages() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDuplicatePages(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, _ := api.PageCountFile(fixture)
+
+	result, err := DuplicatePages(mockContext(), fixture, []int{1, originalPages, 1})
+	if err != nil {
+		t.Fatalf("DuplicatePages() error = %v", err)
+	}
+	defer CleanupTempFiles(result.Path)
+
+	if err := api.ValidateFile(result.Path, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+	if result.PageCount != originalPages+3 {
+		t.Errorf("DuplicatePages() page count = %d, want %d", result.PageCount, originalPages+3)
+	}
+}
+
+func TestDuplicatePages_Errors(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := DuplicatePages(mockContext(), fixture, nil); !errors.Is(err, ErrNoPagesSelected) {
+		t.Errorf("DuplicatePages() error = %v, want %v", err, ErrNoPagesSelected)
+	}
+	if _, err := DuplicatePages(mockContext(), fixture, []int{2}); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("DuplicatePages() error = %v, want %v", err, ErrPageOutOfRange)
+	}
+	if _, err := DuplicatePages(mockContext(), "nonexistent.pdf", []int{1}); err == nil {
+		t.Error("DuplicatePages() should return error for missing file")
+	}
+}
+
+func TestReorderPages_OutOfRange(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := ReorderPages(mockContext(), fixture, []int{1, 2}); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("ReorderPages() error = %v, want %v", err, ErrPageOutOfRange)
+	}
+}
+
+func TestInsertBlankPages(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, _ := api.PageCountFile(fixture)
+	padded := (originalPages + 1 + 3) / 4 * 4
+
+	tests := []struct {
+		name      string
+		positions []int
+		opts      BlankPageOptions
+		wantPages int
+	}{
+		{"positions", []int{0, 1, originalPages}, BlankPageOptions{}, originalPages + 3},
+		{"repeated position", []int{2, 2}, BlankPageOptions{}, originalPages + 1},
+		{"after every page", nil, BlankPageOptions{AfterEveryPage: true}, originalPages * 2},
+		{"front then pad", []int{0}, BlankPageOptions{PadToMultiple: 4}, padded},
+		{"already padded", nil, BlankPageOptions{PadToMultiple: 1}, originalPages},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := InsertBlankPages(mockContext(), fixture, tt.positions, tt.opts)
+			if err != nil {
+				t.Fatalf("InsertBlankPages() error = %v", err)
+			}
+			defer CleanupTempFiles(result.Path)
+
+			if err := api.ValidateFile(result.Path, nil); err != nil {
+				t.Errorf("Output is not valid PDF: %v", err)
+			}
+			if result.PageCount != tt.wantPages {
+				t.Errorf("InsertBlankPages() page count = %d, want %d", result.PageCount, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestInsertBlankPages_MatchesNeighbourSize(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	result, err := InsertBlankPages(mockContext(), fixture, []int{0}, BlankPageOptions{AfterEveryPage: true})
+	if err != nil {
+		t.Fatalf("InsertBlankPages() error = %v", err)
+	}
+	defer CleanupTempFiles(result.Path)
+
+	original, err := api.PageDimsFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page dimensions: %v", err)
+	}
+	dims, err := api.PageDimsFile(result.Path)
+	if err != nil {
+		t.Fatalf("Failed to get page dimensions: %v", err)
+	}
+
+	// Layout: blank, page 1, blank, page 2, blank, ...
+	if dims[0] != original[0] {
+		t.Errorf("Front blank page size = %v, want %v", dims[0], original[0])
+	}
+	for i, want := range original {
+		if dims[2*i+1] != want || dims[2*i+2] != want {
+			t.Errorf("Page %d and its blank = %v, %v, want %v", i+1, dims[2*i+1], dims[2*i+2], want)
+		}
+	}
+}
+
+func TestInsertBlankPages_MixedSizes(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	// Give page 2 a distinct size
+	pdfCtx, err := readPDFContext(fixture, model.INSERTPAGESAFTER)
+	if err != nil {
+		t.Fatalf("Failed to read PDF: %v", err)
+	}
+	pageDict, _, _, err := pdfCtx.PageDict(2, false)
+	if err != nil {
+		t.Fatalf("Failed to read page 2: %v", err)
+	}
+	pageDict.Update("MediaBox", types.RectForDim(300, 400).Array())
+
+	mixed := filepath.Join(t.TempDir(), "mixed.pdf")
+	if err := api.WriteContextFile(pdfCtx, mixed); err != nil {
+		t.Fatalf("Failed to write PDF: %v", err)
+	}
+
+	result, err := InsertBlankPages(mockContext(), mixed, []int{1, 2, 3}, BlankPageOptions{})
+	if err != nil {
+		t.Fatalf("InsertBlankPages() error = %v", err)
+	}
+	defer CleanupTempFiles(result.Path)
+
+	original, _ := api.PageDimsFile(mixed)
+	dims, err := api.PageDimsFile(result.Path)
+	if err != nil {
+		t.Fatalf("Failed to get page dimensions: %v", err)
+	}
+
+	// Layout: page 1, blank, page 2, blank, page 3, blank, page 4, ...
+	for i := 0; i < 3; i++ {
+		if dims[2*i] != original[i] || dims[2*i+1] != original[i] {
+			t.Errorf("Page %d and its blank = %v, %v, want %v", i+1, dims[2*i], dims[2*i+1], original[i])
+		}
+	}
+	if dims[6] != original[3] {
+		t.Errorf("Page 4 = %v, want %v", dims[6], original[3])
+	}
+}
+
+func TestInsertBlankPages_Errors(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := InsertBlankPages(mockContext(), fixture, nil, BlankPageOptions{}); !errors.Is(err, ErrNoPagesSelected) {
+		t.Errorf("InsertBlankPages() error = %v, want %v", err, ErrNoPagesSelected)
+	}
+	if _, err := InsertBlankPages(mockContext(), fixture, []int{2}, BlankPageOptions{}); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("InsertBlankPages() error = %v, want %v", err, ErrPageOutOfRange)
+	}
+	if _, err := InsertBlankPages(mockContext(), fixture, nil, BlankPageOptions{PadToMultiple: -2}); err == nil {
+		t.Error("InsertBlankPages() should return error for negative multiple")
+	}
+}
+
+func TestReversePages(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, _ := api.PageCountFile(fixture)
+
+	result, err := ReversePages(mockContext(), fixture)
+	if err != nil {
+		t.Fatalf("ReversePages() error = %v", err)
+	}
+	defer CleanupTempFiles(result.Path)
+
+	if err := api.ValidateFile(result.Path, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+	if result.PageCount != originalPages {
+		t.Errorf("ReversePages() page count = %d, want %d", result.PageCount, originalPages)
+	}
+}
+
+func TestExtractOddEven(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, _ := api.PageCountFile(fixture)
+
+	odd, err := ExtractOdd(mockContext(), fixture)
+	if err != nil {
+		t.Fatalf("ExtractOdd() error = %v", err)
+	}
+	defer CleanupTempFiles(odd.Path)
+
+	even, err := ExtractEven(mockContext(), fixture)
+	if err != nil {
+		t.Fatalf("ExtractEven() error = %v", err)
+	}
+	defer CleanupTempFiles(even.Path)
+
+	if odd.PageCount != (originalPages+1)/2 {
+		t.Errorf("ExtractOdd() page count = %d, want %d", odd.PageCount, (originalPages+1)/2)
+	}
+	if even.PageCount != originalPages/2 {
+		t.Errorf("ExtractEven() page count = %d, want %d", even.PageCount, originalPages/2)
+	}
+}
+
+func TestExtractEven_SinglePage(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := ExtractEven(mockContext(), fixture); !errors.Is(err, ErrNoPagesSelected) {
+		t.Errorf("ExtractEven() error = %v, want %v", err, ErrNoPagesSelected)
+	}
+}
@@ -0,0 +1,219 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+// roundRobinKeyword switches a pattern of document letters into cyclic mode
+const roundRobinKeyword = "round-robin"
+
+var (
+	// explicitToken matches "A" (all pages), "A3" (one page) or "A2-4" (a range)
+	explicitToken = regexp.MustCompile(`^([A-Z])(?:(\d+)(?:-(\d+))?)?$`)
+	// weightedToken matches "A" or "A:2" (two pages of A per cycle)
+	weightedToken = regexp.MustCompile(`^([A-Z])(?::(\d+))?$`)
+)
+
+// MergePattern collates pages from several documents according to a pattern.
+// Documents are labelled A, B, C, ... in order; each document's PageOrder is applied first.
+// Supported patterns:
+//
+//	"A1 B1 C1 A2 B2 C2"  explicit pages; "A" alone means all of A, "A2-4" a range
+//	"A,B,C round-robin"  one page from each document in turn until all are used up
+//	"A:2,B:1"            two pages of A, then one of B, repeated until all are used up
+func MergePattern(ctx context.Context, docs []PDFDocument, pattern string) (*PDFDocument, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents to merge")
+	}
+	if len(docs) > 26 {
+		return nil, fmt.Errorf("too many documents for a pattern: %d (max 26)", len(docs))
+	}
+
+	pageCounts := make([]int, len(docs))
+	for i, doc := range docs {
+		if len(doc.PageOrder) > 0 {
+			pageCounts[i] = len(doc.PageOrder)
+			continue
+		}
+		count, err := api.PageCountFile(doc.Path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", doc.Name, err)
+		}
+		pageCounts[i] = count
+	}
+
+	selection, err := parseMergePattern(pattern, pageCounts)
+	if err != nil {
+		return nil, err
+	}
+
+	safeEmit(ctx, "combine:log", fmt.Sprintf("Collating %d pages from %d files", len(selection), len(docs)))
+
+	merged, err := mergeContexts(docs)
+	if err != nil {
+		return nil, fmt.Errorf("merge failed: %w", err)
+	}
+
+	// After merge, document i's pages follow the pages of all documents before it
+	offsets := make([]int, len(docs))
+	for i := 1; i < len(docs); i++ {
+		offsets[i] = offsets[i-1] + pageCounts[i-1]
+	}
+
+	pageOrder := make([]int, len(selection))
+	for i, p := range selection {
+		pageOrder[i] = offsets[p.doc] + p.page
+	}
+
+	pdfCtx, err := pdfcpu.ExtractPages(merged, pageOrder, false)
+	if err != nil {
+		return nil, fmt.Errorf("collate failed: %w", err)
+	}
+
+	// Create temp output file
+	outputPath, err := CreateTempFile("collated", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	if err := api.WriteContextFile(pdfCtx, outputPath); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("collate failed: %w", err)
+	}
+
+	return GetPDFInfo(outputPath)
+}
+
+// patternPage is a page reference in a merge pattern: a document index and a 1-indexed page
+type patternPage struct {
+	doc  int
+	page int
+}
+
+// parseMergePattern resolves a merge pattern into a page sequence, given the page count of each document
+func parseMergePattern(pattern string, pageCounts []int) ([]patternPage, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(pattern))
+
+	cyclic := false
+	if trimmed, ok := strings.CutSuffix(normalized, strings.ToUpper(roundRobinKeyword)); ok {
+		cyclic = true
+		normalized = trimmed
+	}
+
+	tokens := strings.FieldsFunc(normalized, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("merge pattern is empty")
+	}
+
+	// Weights imply cyclic mode even without the keyword
+	for _, token := range tokens {
+		if strings.Contains(token, ":") {
+			cyclic = true
+			break
+		}
+	}
+
+	if cyclic {
+		return parseCyclicPattern(tokens, pageCounts)
+	}
+	return parseExplicitPattern(tokens, pageCounts)
+}
+
+// parseExplicitPattern handles patterns like "A1 B1 A2-3 C"
+func parseExplicitPattern(tokens []string, pageCounts []int) ([]patternPage, error) {
+	var pages []patternPage
+	for _, token := range tokens {
+		m := explicitToken.FindStringSubmatch(token)
+		if m == nil {
+			return nil, fmt.Errorf("invalid pattern token %q", token)
+		}
+
+		doc, err := patternDocIndex(m[1], pageCounts)
+		if err != nil {
+			return nil, err
+		}
+
+		start, end := 1, pageCounts[doc]
+		if m[2] != "" {
+			start, _ = strconv.Atoi(m[2])
+			end = start
+			if m[3] != "" {
+				end, _ = strconv.Atoi(m[3])
+			}
+		}
+
+		if start < 1 || end > pageCounts[doc] || start > end {
+			return nil, fmt.Errorf("pattern token %q out of range: %s has %d pages", token, m[1], pageCounts[doc])
+		}
+
+		for page := start; page <= end; page++ {
+			pages = append(pages, patternPage{doc: doc, page: page})
+		}
+	}
+	return pages, nil
+}
+
+// parseCyclicPattern handles patterns like "A,B,C" (round-robin) and "A:2,B:1",
+// taking the given number of pages from each document per cycle until all are used up
+func parseCyclicPattern(tokens []string, pageCounts []int) ([]patternPage, error) {
+	type step struct {
+		doc    int
+		weight int
+	}
+
+	var steps []step
+	for _, token := range tokens {
+		m := weightedToken.FindStringSubmatch(token)
+		if m == nil {
+			return nil, fmt.Errorf("invalid pattern token %q", token)
+		}
+
+		doc, err := patternDocIndex(m[1], pageCounts)
+		if err != nil {
+			return nil, err
+		}
+
+		weight := 1
+		if m[2] != "" {
+			weight, _ = strconv.Atoi(m[2])
+			if weight < 1 {
+				return nil, fmt.Errorf("pattern token %q: page count must be at least 1", token)
+			}
+		}
+		steps = append(steps, step{doc: doc, weight: weight})
+	}
+
+	var pages []patternPage
+	next := make([]int, len(pageCounts))
+	for {
+		added := false
+		for _, s := range steps {
+			for n := 0; n < s.weight && next[s.doc] < pageCounts[s.doc]; n++ {
+				next[s.doc]++
+				pages = append(pages, patternPage{doc: s.doc, page: next[s.doc]})
+				added = true
+			}
+		}
+		if !added {
+			return pages, nil
+		}
+	}
+}
+
+// patternDocIndex maps a document letter to its index
+func patternDocIndex(letter string, pageCounts []int) (int, error) {
+	doc := int(letter[0] - 'A')
+	if doc >= len(pageCounts) {
+		return 0, fmt.Errorf("pattern refers to document %s, but only %d documents were given", letter, len(pageCounts))
+	}
+	return doc, nil
+}
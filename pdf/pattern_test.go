@@ -0,0 +1,123 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+func TestMergePattern(t *testing.T) {
+	multiPage := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	singlePage := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(multiPage); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	docA, err := GetPDFInfo(multiPage)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+	docB, err := GetPDFInfo(singlePage)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+	docs := []PDFDocument{*docA, *docB}
+
+	tests := []struct {
+		pattern   string
+		wantPages int
+	}{
+		{"A1 B1 A2", 3},
+		{"A,B round-robin", docA.PageCount + docB.PageCount},
+		{"A:2,B:1", docA.PageCount + docB.PageCount},
+		{"B A1-3 B", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			merged, err := MergePattern(mockContext(), docs, tt.pattern)
+			if err != nil {
+				t.Fatalf("MergePattern() error = %v", err)
+			}
+			defer CleanupTempFiles(merged.Path)
+
+			if err := api.ValidateFile(merged.Path, nil); err != nil {
+				t.Errorf("Output is not valid PDF: %v", err)
+			}
+			if merged.PageCount != tt.wantPages {
+				t.Errorf("MergePattern() page count = %d, want %d", merged.PageCount, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestMergePattern_InvalidPattern(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	doc, err := GetPDFInfo(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get PDF info: %v", err)
+	}
+
+	_, err = MergePattern(mockContext(), []PDFDocument{*doc, *doc}, "A1 C1")
+	if err == nil {
+		t.Error("MergePattern() should return error for unknown document letter")
+	}
+}
+
+func TestParseMergePattern(t *testing.T) {
+	pageCounts := []int{3, 2, 1}
+
+	tests := []struct {
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{"A1 B1 C1 A2 B2", "A1 B1 C1 A2 B2", false},
+		{"a1, b2", "A1 B2", false},
+		{"A B", "A1 A2 A3 B1 B2", false},
+		{"A2-3 C", "A2 A3 C1", false},
+		{"A,B,C round-robin", "A1 B1 C1 A2 B2 A3", false},
+		{"A, B ROUND-ROBIN", "A1 B1 A2 B2 A3", false},
+		{"A:2,B:1", "A1 A2 B1 A3 B2", false},
+		{"B:2 A", "B1 B2 A1 A2 A3", false},
+		{"", "", true},
+		{"round-robin", "", true},
+		{"D1", "", true},
+		{"A4", "", true},
+		{"A3-2", "", true},
+		{"A0", "", true},
+		{"A:0,B", "", true},
+		{"A1:2", "", true},
+		{"1A", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			pages, err := parseMergePattern(tt.pattern, pageCounts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMergePattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := ""
+			for i, p := range pages {
+				if i > 0 {
+					got += " "
+				}
+				got += fmt.Sprintf("%c%d", 'A'+p.doc, p.page)
+			}
+			if got != tt.want {
+				t.Errorf("parseMergePattern(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
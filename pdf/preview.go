@@ -0,0 +1,227 @@
+package pdf
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Preview images are larger than thumbnails so compression artifacts are visible
+const (
+	previewWidth  = 900
+	previewHeight = 1200
+)
+
+// PreviewCompression compresses a single page with a preset and renders it next to the original
+// at the same resolution, so the quality can be judged before compressing the whole file.
+// The size ratio compares the compressed page with its share of the original file, so it is
+// only an estimate for the whole document.
+func PreviewCompression(ctx context.Context, path string, preset CompressionPreset, pageIndex int) (*CompressionPreview, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+
+	pageCount, err := getPageCount(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+	if pageIndex < 0 || pageIndex >= pageCount {
+		return nil, fmt.Errorf("page index %d out of range (0-%d)", pageIndex, pageCount-1)
+	}
+	pageNum := pageIndex + 1
+
+	gsPath, err := GetGhostscriptPath()
+	if err != nil {
+		return nil, fmt.Errorf("ghostscript not available: %w. %s", err, GhostscriptInstallInstructions())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Previewing page %d with preset %s", pageNum, preset))
+
+	// The page's share of the file is the baseline, so resources it shares with other pages
+	// count only in part, as they do in the whole-file estimate
+	originalShares, err := pageShares(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ghostscript compresses just the page range; lossless optimization has no page range,
+	// so it runs on the whole file and the page's share is measured the same way
+	var compressedPath string
+	var compressedPageSize int64
+	var compressedPageNum int
+	if preset == PresetLossless {
+		result, err := optimizeLossless(path, info.Size())
+		if err != nil {
+			return nil, err
+		}
+		compressedPath, compressedPageNum = result.OutputPath, pageNum
+		defer CleanupTempFiles(compressedPath)
+
+		compressedShares, err := pageShares(compressedPath)
+		if err != nil {
+			return nil, err
+		}
+		compressedPageSize = compressedShares[pageIndex]
+	} else {
+		opts, ok := presetOptions[preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %q", preset)
+		}
+		if compressedPath, err = CreateTempFile("preview_compressed", ".pdf"); err != nil {
+			return nil, fmt.Errorf("cannot create temp file: %w", err)
+		}
+		defer CleanupTempFiles(compressedPath)
+
+		pageRange := []string{fmt.Sprintf("-dFirstPage=%d", pageNum), fmt.Sprintf("-dLastPage=%d", pageNum)}
+		args := append(pageRange, ghostscriptArgs(opts, path, compressedPath)...)
+		if err := runGhostscript(ctx, gsPath, args, nil); err != nil {
+			return nil, err
+		}
+		compressedInfo, err := os.Stat(compressedPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read compressed page: %w", err)
+		}
+		compressedPageSize, compressedPageNum = compressedInfo.Size(), 1
+	}
+
+	original, err := renderPreview(ctx, gsPath, path, pageNum, pageIndex)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := renderPreview(ctx, gsPath, compressedPath, compressedPageNum, pageIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &CompressionPreview{
+		PageIndex:          pageIndex,
+		Preset:             preset,
+		Original:           original,
+		Compressed:         compressed,
+		OriginalPageSize:   originalShares[pageIndex],
+		CompressedPageSize: compressedPageSize,
+	}
+	if preview.OriginalPageSize > 0 {
+		preview.SizeRatio = float64(preview.CompressedPageSize) / float64(preview.OriginalPageSize)
+		preview.EstimatedSize = int64(float64(info.Size()) * preview.SizeRatio)
+	}
+
+	safeEmit(ctx, "compress:log", fmt.Sprintf("Page %d: %s -> %s (estimated file size %s)", pageNum,
+		FormatFileSize(preview.OriginalPageSize), FormatFileSize(preview.CompressedPageSize), FormatFileSize(preview.EstimatedSize)))
+
+	return preview, nil
+}
+
+// renderPreview renders a page at preview size, bypassing the thumbnail cache
+func renderPreview(ctx context.Context, gsPath, pdfPath string, pageNum, pageIndex int) (*ThumbnailResult, error) {
+	pngPath, err := CreateTempFile("preview", ".png")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+	defer CleanupTempFiles(pngPath)
+
+	if err := renderPagePNG(ctx, gsPath, pdfPath, pageNum, previewWidth, previewHeight, pngPath); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(pngPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read preview: %w", err)
+	}
+
+	return &ThumbnailResult{
+		PageIndex: pageIndex,
+		ImageData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(data),
+		Width:     previewWidth,
+		Height:    previewHeight,
+	}, nil
+}
+
+// pageShares estimates how many bytes of a PDF each page accounts for. Each stream is split
+// evenly between the pages that use it, and everything else in the file is spread over all pages,
+// so the shares add up to the file size.
+func pageShares(path string) ([]int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access file: %w", err)
+	}
+	pdfCtx, err := api.ReadContextFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+
+	pageStreams := make([]map[int]int64, pdfCtx.PageCount)
+	users := make(map[int]int)
+	var streamBytes int64
+	for i := range pageStreams {
+		pageDict, _, _, err := pdfCtx.PageDict(i+1, false)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read page %d: %w", i+1, err)
+		}
+		pageStreams[i] = make(map[int]int64)
+		collectStreams(pdfCtx, pageDict, pageStreams[i], make(map[int]bool))
+		for objNr, size := range pageStreams[i] {
+			if users[objNr] == 0 {
+				streamBytes += size
+			}
+			users[objNr]++
+		}
+	}
+
+	overhead := float64(max(0, info.Size()-streamBytes)) / float64(max(1, pdfCtx.PageCount))
+	shares := make([]int64, pdfCtx.PageCount)
+	for i, streams := range pageStreams {
+		share := overhead
+		for objNr, size := range streams {
+			share += float64(size) / float64(users[objNr])
+		}
+		shares[i] = int64(math.Round(share))
+	}
+	return shares, nil
+}
+
+// collectStreams records the stored size of every stream reachable from o, without
+// following links back up the page tree or over to other pages
+func collectStreams(pdfCtx *model.Context, o types.Object, streams map[int]int64, visited map[int]bool) {
+	switch v := o.(type) {
+	case types.IndirectRef:
+		objNr := v.ObjectNumber.Value()
+		if visited[objNr] {
+			return
+		}
+		visited[objNr] = true
+		obj, err := pdfCtx.Dereference(v)
+		if err != nil {
+			return
+		}
+		if d, ok := obj.(types.Dict); ok && (isType(d, "Page") || isType(d, "Pages")) {
+			return
+		}
+		if sd, ok := obj.(types.StreamDict); ok {
+			streams[objNr] = streamSize(sd)
+		}
+		collectStreams(pdfCtx, obj, streams, visited)
+	case types.Dict:
+		for key, value := range v {
+			if key != "Parent" {
+				collectStreams(pdfCtx, value, streams, visited)
+			}
+		}
+	case types.StreamDict:
+		collectStreams(pdfCtx, v.Dict, streams, visited)
+	case types.Array:
+		for _, value := range v {
+			collectStreams(pdfCtx, value, streams, visited)
+		}
+	}
+}
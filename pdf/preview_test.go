@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreviewCompression(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	for _, preset := range []CompressionPreset{PresetScreen, PresetLossless} {
+		t.Run(string(preset), func(t *testing.T) {
+			preview, err := PreviewCompression(mockContext(), fixturePath, preset, 1)
+			if err != nil {
+				t.Fatalf("PreviewCompression() error = %v", err)
+			}
+
+			for name, image := range map[string]*ThumbnailResult{"original": preview.Original, "compressed": preview.Compressed} {
+				if image == nil || !strings.HasPrefix(image.ImageData, "data:image/png;base64,") {
+					t.Errorf("%s preview is missing", name)
+					continue
+				}
+				if image.Width != previewWidth || image.Height != previewHeight {
+					t.Errorf("%s preview is %dx%d, want %dx%d", name, image.Width, image.Height, previewWidth, previewHeight)
+				}
+			}
+			if preview.PageIndex != 1 {
+				t.Errorf("PageIndex = %d, want 1", preview.PageIndex)
+			}
+			if preview.OriginalPageSize <= 0 || preview.CompressedPageSize <= 0 || preview.SizeRatio <= 0 {
+				t.Errorf("sizes not measured: %+v", preview)
+			}
+		})
+	}
+}
+
+func TestPreviewCompression_InvalidPage(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	for _, pageIndex := range []int{-1, 1} {
+		if _, err := PreviewCompression(mockContext(), fixturePath, PresetEbook, pageIndex); err == nil {
+			t.Errorf("PreviewCompression(page %d) should return error for 1-page PDF", pageIndex)
+		}
+	}
+}
+
+func TestPageShares(t *testing.T) {
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	info, err := os.Stat(fixturePath)
+	if os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	shares, err := pageShares(fixturePath)
+	if err != nil {
+		t.Fatalf("pageShares() error = %v", err)
+	}
+
+	var total int64
+	for i, share := range shares {
+		if share <= 0 {
+			t.Errorf("page %d share = %d, want > 0", i+1, share)
+		}
+		total += share
+	}
+	// Each share is rounded to whole bytes, so allow one byte per page either way
+	if diff := total - info.Size(); diff > int64(len(shares)) || diff < -int64(len(shares)) {
+		t.Errorf("shares add up to %d, want about %d", total, info.Size())
+	}
+}
@@ -0,0 +1,128 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ErrNoRotations is returned when ApplyRotations is given an empty rotation list
+var ErrNoRotations = errors.New("no rotations given")
+
+// ApplyRotations creates a new PDF with the given rotations applied.
+// Only pages with a non-zero rotation are modified, and each rotation is
+// added to the page's existing /Rotate value. A list where every rotation is 0°
+// still produces a copy, with PagesRotated set to 0.
+func ApplyRotations(ctx context.Context, path string, rotations []PageRotation) (*RotateResult, error) {
+	if len(rotations) == 0 {
+		return nil, ErrNoRotations
+	}
+
+	safeEmit(ctx, "rotate:progress", ProgressUpdate{
+		Percent: 10,
+		Message: "Preparing rotation...",
+	})
+
+	pdfCtx, err := readPDFContext(path, model.ROTATE)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+
+	// Validate everything before modifying any page
+	var pending []PageRotation
+	seen := make(map[int]bool)
+	for _, r := range rotations {
+		if r.PageNum < 1 || r.PageNum > pdfCtx.PageCount {
+			return nil, fmt.Errorf("page %d out of range (1-%d)", r.PageNum, pdfCtx.PageCount)
+		}
+		// Rotations are added to the page, so a repeated page would be rotated twice
+		if seen[r.PageNum] {
+			return nil, fmt.Errorf("page %d listed more than once", r.PageNum)
+		}
+		seen[r.PageNum] = true
+		if r.Rotation%90 != 0 {
+			return nil, fmt.Errorf("invalid rotation %d for page %d: must be a multiple of 90", r.Rotation, r.PageNum)
+		}
+		if normalizeRotation(int(r.Rotation)) != 0 {
+			pending = append(pending, r)
+		}
+	}
+
+	rotatedPages := make(map[int]bool)
+	for i, r := range pending {
+		safeEmit(ctx, "rotate:log", fmt.Sprintf("Rotating page %d by %d°...", r.PageNum, r.Rotation))
+
+		if err := rotatePage(pdfCtx, r.PageNum, int(r.Rotation)); err != nil {
+			return nil, fmt.Errorf("cannot rotate page %d: %w", r.PageNum, err)
+		}
+		rotatedPages[r.PageNum] = true
+
+		safeEmit(ctx, "rotate:progress", ProgressUpdate{
+			Percent: 10 + (i+1)*70/len(pending),
+			Message: fmt.Sprintf("Rotating page %d...", r.PageNum),
+		})
+	}
+
+	// Create temp output file
+	outputPath, err := CreateTempFile("rotated", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	safeEmit(ctx, "rotate:progress", ProgressUpdate{
+		Percent: 85,
+		Message: "Writing PDF...",
+	})
+
+	if err := api.WriteContextFile(pdfCtx, outputPath); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot write rotated PDF: %w", err)
+	}
+
+	// Get output file size
+	outputInfo, err := os.Stat(outputPath)
+	if err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot read output file: %w", err)
+	}
+
+	safeEmit(ctx, "rotate:log", fmt.Sprintf("Rotated %d pages", len(rotatedPages)))
+	safeEmit(ctx, "rotate:log", fmt.Sprintf("Output size: %s", FormatFileSize(outputInfo.Size())))
+
+	safeEmit(ctx, "rotate:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return &RotateResult{
+		Success:      true,
+		PagesRotated: len(rotatedPages),
+		OutputSize:   outputInfo.Size(),
+		OutputPath:   outputPath,
+	}, nil
+}
+
+// rotatePage adds angle to the page's effective rotation, including any
+// value inherited from the page tree
+func rotatePage(pdfCtx *model.Context, pageNum, angle int) error {
+	d, _, inherited, err := pdfCtx.PageDict(pageNum, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return fmt.Errorf("page %d not found", pageNum)
+	}
+
+	d.Update("Rotate", types.Integer(normalizeRotation(inherited.Rotate+angle)))
+	return nil
+}
+
+// normalizeRotation maps any multiple of 90 into the range 0-270
+func normalizeRotation(angle int) int {
+	return ((angle % 360) + 360) % 360
+}
@@ -0,0 +1,205 @@
+package pdf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// pageRotation returns the effective /Rotate value of a page
+func pageRotation(t *testing.T, path string, pageNum int) int {
+	t.Helper()
+
+	pdfCtx, err := api.ReadContextFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read PDF: %v", err)
+	}
+	_, _, inherited, err := pdfCtx.PageDict(pageNum, false)
+	if err != nil {
+		t.Fatalf("Failed to read page %d: %v", pageNum, err)
+	}
+	return inherited.Rotate
+}
+
+func TestApplyRotations_PerPage(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	if originalPages < 4 {
+		t.Skip("Need at least 4 pages for this test")
+	}
+
+	original := make(map[int]int)
+	for page := 1; page <= 4; page++ {
+		original[page] = pageRotation(t, fixture, page)
+	}
+
+	rotations := []PageRotation{
+		{PageNum: 1, Rotation: Rotate90},
+		{PageNum: 2, Rotation: Rotate180},
+		{PageNum: 3, Rotation: Rotate0},
+		{PageNum: 4, Rotation: Rotate270},
+	}
+
+	result, err := ApplyRotations(mockContext(), fixture, rotations)
+	if err != nil {
+		t.Fatalf("ApplyRotations() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if !result.Success {
+		t.Error("ApplyRotations().Success = false, want true")
+	}
+
+	// Page 3 had no rotation and should not be counted
+	if result.PagesRotated != 3 {
+		t.Errorf("ApplyRotations().PagesRotated = %d, want 3", result.PagesRotated)
+	}
+
+	// Verify output is valid PDF
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+
+	for _, r := range rotations {
+		want := normalizeRotation(original[r.PageNum] + int(r.Rotation))
+		if got := pageRotation(t, result.OutputPath, r.PageNum); got != want {
+			t.Errorf("Page %d rotation = %d, want %d", r.PageNum, got, want)
+		}
+	}
+
+	outputPages, _ := api.PageCountFile(result.OutputPath)
+	if outputPages != originalPages {
+		t.Errorf("Output page count = %d, want %d", outputPages, originalPages)
+	}
+}
+
+func TestApplyRotations_ComposesWithExisting(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	original := pageRotation(t, fixture, 1)
+
+	first, err := ApplyRotations(mockContext(), fixture, []PageRotation{{PageNum: 1, Rotation: Rotate90}})
+	if err != nil {
+		t.Fatalf("ApplyRotations() error = %v", err)
+	}
+	defer CleanupTempFiles(first.OutputPath)
+
+	second, err := ApplyRotations(mockContext(), first.OutputPath, []PageRotation{{PageNum: 1, Rotation: Rotate270}})
+	if err != nil {
+		t.Fatalf("ApplyRotations() error = %v", err)
+	}
+	defer CleanupTempFiles(second.OutputPath)
+
+	// 90 + 270 brings the page back to where it started
+	if got := pageRotation(t, second.OutputPath, 1); got != original {
+		t.Errorf("Page rotation = %d, want %d", got, original)
+	}
+}
+
+func TestApplyRotations_NoRotations(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	original := pageRotation(t, fixture, 1)
+
+	// Upright pages come back as 0° suggestions, which must not be an error
+	result, err := ApplyRotations(mockContext(), fixture, []PageRotation{{PageNum: 1, Rotation: Rotate0}})
+	if err != nil {
+		t.Fatalf("ApplyRotations() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if result.PagesRotated != 0 {
+		t.Errorf("ApplyRotations().PagesRotated = %d, want 0", result.PagesRotated)
+	}
+	if got := pageRotation(t, result.OutputPath, 1); got != original {
+		t.Errorf("Page rotation = %d, want %d", got, original)
+	}
+}
+
+func TestApplyRotations_EmptyList(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := ApplyRotations(mockContext(), fixture, nil); !errors.Is(err, ErrNoRotations) {
+		t.Errorf("ApplyRotations() error = %v, want ErrNoRotations", err)
+	}
+}
+
+func TestApplyRotations_InvalidInput(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	tests := []struct {
+		name     string
+		rotation PageRotation
+	}{
+		{"page zero", PageRotation{PageNum: 0, Rotation: Rotate90}},
+		{"page out of range", PageRotation{PageNum: 2, Rotation: Rotate90}},
+		{"invalid angle", PageRotation{PageNum: 1, Rotation: 45}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplyRotations(mockContext(), fixture, []PageRotation{tt.rotation}); err == nil {
+				t.Error("ApplyRotations() should return error")
+			}
+		})
+	}
+}
+
+func TestApplyRotations_DuplicatePage(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	// Two 90° entries for one page must not add up to 180°
+	rotations := []PageRotation{
+		{PageNum: 1, Rotation: Rotate90},
+		{PageNum: 1, Rotation: Rotate90},
+	}
+	if _, err := ApplyRotations(mockContext(), fixture, rotations); err == nil {
+		t.Error("ApplyRotations() should return error for a page listed twice")
+	}
+}
+
+func TestNormalizeRotation(t *testing.T) {
+	tests := []struct {
+		angle    int
+		expected int
+	}{
+		{0, 0},
+		{90, 90},
+		{360, 0},
+		{450, 90},
+		{-90, 270},
+		{-180, 180},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeRotation(tt.angle); got != tt.expected {
+			t.Errorf("normalizeRotation(%d) = %d, want %d", tt.angle, got, tt.expected)
+		}
+	}
+}
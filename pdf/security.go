@@ -0,0 +1,117 @@
+package pdf
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+var (
+	// ErrPasswordProtected is returned when a PDF needs a password to open
+	ErrPasswordProtected = errors.New("PDF is password-protected")
+
+	// ErrIncorrectPassword is returned when the password given to UnlockPDF doesn't open the PDF
+	ErrIncorrectPassword = errors.New("incorrect password")
+)
+
+// UnlockPDF decrypts a password-protected PDF into a temp file and returns its path.
+// Either the user (open) password or the owner password is accepted.
+func UnlockPDF(path, password string) (string, error) {
+	outputPath, err := CreateTempFile("unlocked", ".pdf")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = password
+	conf.OwnerPW = password
+
+	if err := api.DecryptFile(path, outputPath, conf); err != nil {
+		CleanupTempFiles(outputPath)
+		if errors.Is(err, pdfcpu.ErrWrongPassword) {
+			return "", ErrIncorrectPassword
+		}
+		return "", fmt.Errorf("cannot unlock PDF: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// EncryptPDF writes a password-protected copy of a PDF to a temp file and returns its path
+func EncryptPDF(ctx context.Context, path string, opts EncryptOptions) (string, error) {
+	if opts.UserPassword == "" && opts.OwnerPassword == "" {
+		return "", fmt.Errorf("a user or owner password is required")
+	}
+	if opts.OwnerPassword == "" {
+		// Reusing the user password would let anyone who can open the file lift its permissions
+		password, err := randomPassword()
+		if err != nil {
+			return "", err
+		}
+		opts.OwnerPassword = password
+	}
+
+	keyLength := 128
+	if opts.AES256 {
+		keyLength = 256
+	}
+	conf := model.NewAESConfiguration(opts.UserPassword, opts.OwnerPassword, keyLength)
+	conf.Permissions = permissionFlags(opts.Permissions)
+
+	safeEmit(ctx, "encrypt:log", fmt.Sprintf("Encrypting with AES-%d", keyLength))
+	safeEmit(ctx, "encrypt:log", fmt.Sprintf("Allow printing: %t, copying: %t, editing: %t",
+		opts.Permissions.Print, opts.Permissions.Copy, opts.Permissions.Edit))
+
+	outputPath, err := CreateTempFile("encrypted", ".pdf")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	if err := api.EncryptFile(path, outputPath, conf); err != nil {
+		CleanupTempFiles(outputPath)
+		// pdfcpu refuses encrypted input with an unexported error, so match it like ValidatePDF does
+		if errors.Is(err, pdfcpu.ErrWrongPassword) || strings.Contains(err.Error(), "encrypted") {
+			return "", ErrPasswordProtected
+		}
+		return "", fmt.Errorf("cannot encrypt PDF: %w", err)
+	}
+
+	if info, err := os.Stat(outputPath); err == nil {
+		safeEmit(ctx, "encrypt:log", fmt.Sprintf("Output size: %s", FormatFileSize(info.Size())))
+	}
+
+	return outputPath, nil
+}
+
+// randomPassword returns an unguessable password for documents without an owner password
+func randomPassword() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("cannot generate owner password: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// permissionFlags converts permissions into pdfcpu's user access flags
+func permissionFlags(p PDFPermissions) model.PermissionFlags {
+	flags := model.PermissionsNone
+	if p.Print {
+		flags |= model.PermissionPrintRev2 | model.PermissionPrintRev3
+	}
+	if p.Copy {
+		flags |= model.PermissionExtract | model.PermissionExtractRev3
+	}
+	if p.Edit {
+		flags |= model.PermissionModify | model.PermissionModAnnFillForm |
+			model.PermissionFillRev3 | model.PermissionAssembleRev3
+	}
+	return flags
+}
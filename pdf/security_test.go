@@ -0,0 +1,194 @@
+package pdf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// encryptFixture writes a copy of simple-1page.pdf protected by the given passwords
+func encryptFixture(t *testing.T, userPW, ownerPW string) string {
+	t.Helper()
+
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	encrypted := filepath.Join(t.TempDir(), "encrypted.pdf")
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = userPW
+	conf.OwnerPW = ownerPW
+	if err := api.EncryptFile(fixture, encrypted, conf); err != nil {
+		t.Fatalf("Failed to encrypt fixture: %v", err)
+	}
+	return encrypted
+}
+
+func TestUnlockPDF(t *testing.T) {
+	encrypted := encryptFixture(t, "userpass", "ownerpass")
+
+	if err := ValidatePDF(encrypted); !errors.Is(err, ErrPasswordProtected) {
+		t.Fatalf("ValidatePDF() error = %v, want ErrPasswordProtected", err)
+	}
+
+	for _, password := range []string{"userpass", "ownerpass"} {
+		t.Run(password, func(t *testing.T) {
+			unlocked, err := UnlockPDF(encrypted, password)
+			if err != nil {
+				t.Fatalf("UnlockPDF() error = %v", err)
+			}
+			defer CleanupTempFiles(unlocked)
+
+			// The unlocked copy opens without a password
+			if err := ValidatePDF(unlocked); err != nil {
+				t.Errorf("Unlocked PDF is not valid: %v", err)
+			}
+
+			pages, err := api.PageCountFile(unlocked)
+			if err != nil || pages != 1 {
+				t.Errorf("Unlocked page count = %d (err %v), want 1", pages, err)
+			}
+		})
+	}
+}
+
+func TestUnlockPDF_WrongPassword(t *testing.T) {
+	encrypted := encryptFixture(t, "userpass", "ownerpass")
+
+	_, err := UnlockPDF(encrypted, "wrong")
+	if !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("UnlockPDF() error = %v, want ErrIncorrectPassword", err)
+	}
+}
+
+func TestUnlockPDF_InvalidFile(t *testing.T) {
+	_, err := UnlockPDF("/nonexistent/file.pdf", "password")
+	if err == nil {
+		t.Error("UnlockPDF() should return error for non-existent file")
+	}
+	if errors.Is(err, ErrIncorrectPassword) {
+		t.Error("UnlockPDF() should not report a missing file as a wrong password")
+	}
+}
+
+func TestEncryptPDF(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	for _, aes256 := range []bool{false, true} {
+		opts := EncryptOptions{
+			UserPassword:  "userpass",
+			OwnerPassword: "ownerpass",
+			AES256:        aes256,
+			Permissions:   PDFPermissions{Print: true},
+		}
+
+		encrypted, err := EncryptPDF(mockContext(), fixture, opts)
+		if err != nil {
+			t.Fatalf("EncryptPDF(AES256=%t) error = %v", aes256, err)
+		}
+		defer CleanupTempFiles(encrypted)
+
+		if err := ValidatePDF(encrypted); !errors.Is(err, ErrPasswordProtected) {
+			t.Errorf("ValidatePDF() error = %v, want ErrPasswordProtected", err)
+		}
+
+		// Printing is allowed but copying and editing are not
+		conf := model.NewDefaultConfiguration()
+		conf.UserPW = "userpass"
+		conf.OwnerPW = "ownerpass"
+		perms, err := api.GetPermissionsFile(encrypted, conf)
+		if err != nil {
+			t.Fatalf("GetPermissionsFile() error = %v", err)
+		}
+		flags := model.PermissionFlags(uint16(*perms))
+		if flags&model.PermissionPrintRev3 == 0 {
+			t.Error("Expected printing to be allowed")
+		}
+		if flags&(model.PermissionExtract|model.PermissionModify) != 0 {
+			t.Error("Expected copying and editing to be denied")
+		}
+
+		unlocked, err := UnlockPDF(encrypted, "userpass")
+		if err != nil {
+			t.Fatalf("UnlockPDF() error = %v", err)
+		}
+		CleanupTempFiles(unlocked)
+	}
+}
+
+func TestEncryptPDF_NoPassword(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	_, err := EncryptPDF(mockContext(), fixture, EncryptOptions{})
+	if err == nil {
+		t.Error("EncryptPDF() should return error without a password")
+	}
+}
+
+func TestEncryptPDF_UserPasswordOnly(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	encrypted, err := EncryptPDF(mockContext(), fixture, EncryptOptions{UserPassword: "userpass"})
+	if err != nil {
+		t.Fatalf("EncryptPDF() error = %v", err)
+	}
+	defer CleanupTempFiles(encrypted)
+
+	// The user password opens the file but must not lift its permissions
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = "userpass"
+	conf.OwnerPW = "userpass"
+	conf.Permissions = model.PermissionsAll
+	output := filepath.Join(t.TempDir(), "permissions.pdf")
+	if err := api.SetPermissionsFile(encrypted, output, conf); err == nil {
+		t.Error("SetPermissionsFile() with the user password should fail")
+	}
+
+	unlocked, err := UnlockPDF(encrypted, "userpass")
+	if err != nil {
+		t.Fatalf("UnlockPDF() error = %v", err)
+	}
+	CleanupTempFiles(unlocked)
+}
+
+func TestEncryptPDF_AlreadyEncrypted(t *testing.T) {
+	encrypted := encryptFixture(t, "userpass", "ownerpass")
+
+	_, err := EncryptPDF(mockContext(), encrypted, EncryptOptions{UserPassword: "new"})
+	if !errors.Is(err, ErrPasswordProtected) {
+		t.Errorf("EncryptPDF() error = %v, want ErrPasswordProtected", err)
+	}
+}
+
+func TestPermissionFlags(t *testing.T) {
+	none := permissionFlags(PDFPermissions{})
+	if none != model.PermissionsNone {
+		t.Errorf("permissionFlags(none) = %#x, want %#x", none, model.PermissionsNone)
+	}
+
+	all := permissionFlags(PDFPermissions{Print: true, Copy: true, Edit: true})
+	for _, flag := range []model.PermissionFlags{
+		model.PermissionPrintRev3,
+		model.PermissionExtract,
+		model.PermissionModify,
+		model.PermissionAssembleRev3,
+	} {
+		if all&flag == 0 {
+			t.Errorf("permissionFlags(all) missing flag %#x", flag)
+		}
+	}
+}
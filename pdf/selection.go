@@ -0,0 +1,232 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PageSelectionError reports a problem in a page selection expression and where it is,
+// so the UI can highlight the offending text
+type PageSelectionError struct {
+	Msg    string
+	Offset int   // character offset of the problem in the expression
+	Length int   // number of characters to highlight
+	Err    error // ErrPageOutOfRange or ErrNoPagesSelected, if applicable
+}
+
+func (e *PageSelectionError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Offset+1)
+}
+
+func (e *PageSelectionError) Unwrap() error {
+	return e.Err
+}
+
+// ParsePageSelection resolves a page selection expression into 1-indexed page numbers, in order.
+// Terms are comma-separated and follow pdfcpu's syntax:
+//
+//	"5", "1-3", "9-", "-4"   single pages and (open) ranges
+//	"l", "last", "l-2"       last page, last page minus 2; also usable in ranges ("3-l")
+//	"odd", "even"            odd or even pages
+//	"!2", "n2", "!1-3"       remove pages selected so far; a leading negation starts from all pages
+//
+// Pages may repeat ("1,1,2"). Out-of-range pages are an error rather than being ignored.
+// Errors are *PageSelectionError values.
+func ParsePageSelection(expr string, pageCount int) ([]int, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, selectionError(expr, 0, 0, ErrNoPagesSelected, "page selection is empty")
+	}
+
+	var pages []int
+	termStart := 0
+	for i, term := range strings.Split(expr, ",") {
+		p := selectionParser{expr: expr, pos: termStart, end: termStart + len(term), pageCount: pageCount}
+		termStart = p.end + 1
+
+		p.skipSpaces()
+		if p.pos == p.end {
+			return nil, selectionError(expr, p.pos, 0, nil, "empty term")
+		}
+
+		negate := p.expr[p.pos] == '!' || p.expr[p.pos] == 'n' || p.expr[p.pos] == 'N'
+		if negate {
+			p.pos++
+			p.skipSpaces()
+			if p.pos == p.end {
+				return nil, selectionError(expr, p.pos-1, 1, nil, "negation without pages")
+			}
+			if i == 0 {
+				pages = pageSequence(1, pageCount)
+			}
+		}
+
+		selected, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		if negate {
+			pages = removePages(pages, selected)
+		} else {
+			pages = append(pages, selected...)
+		}
+	}
+
+	if len(pages) == 0 {
+		return nil, selectionError(expr, 0, len(expr), ErrNoPagesSelected, "selection matches no pages")
+	}
+	return pages, nil
+}
+
+// selectionParser scans a single comma-separated term, expr[pos:end]
+type selectionParser struct {
+	expr      string
+	pos, end  int
+	pageCount int
+}
+
+// parseTerm resolves the rest of the term (after any negation)
+func (p *selectionParser) parseTerm() ([]int, error) {
+	start := p.pos
+	text := strings.TrimSpace(p.expr[p.pos:p.end])
+
+	switch strings.ToLower(text) {
+	case "odd", "even":
+		first := 1
+		if strings.EqualFold(text, "even") {
+			first = 2
+		}
+		var pages []int
+		for page := first; page <= p.pageCount; page += 2 {
+			pages = append(pages, page)
+		}
+		return pages, nil
+	}
+
+	from, err := p.parseRef(1)
+	if err != nil {
+		return nil, err
+	}
+	thru := from
+
+	p.skipSpaces()
+	if p.pos < p.end && p.expr[p.pos] == '-' {
+		p.pos++
+		if thru, err = p.parseRef(p.pageCount); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+	}
+	if p.pos < p.end {
+		return nil, p.unexpected()
+	}
+
+	if from < 1 || thru > p.pageCount {
+		return nil, selectionError(p.expr, start, len(text), ErrPageOutOfRange,
+			fmt.Sprintf("%q is out of range (document has %d pages)", text, p.pageCount))
+	}
+	if from > thru {
+		return nil, selectionError(p.expr, start, len(text), ErrPageOutOfRange,
+			fmt.Sprintf("%q is a backwards range", text))
+	}
+	return pageSequence(from, thru), nil
+}
+
+// parseRef reads a page reference: a number, "l"/"last", or "l-N".
+// A missing reference yields def, so "-4" and "9-" work as open ranges.
+func (p *selectionParser) parseRef(def int) (int, error) {
+	p.skipSpaces()
+	if p.pos == p.end || p.expr[p.pos] == '-' {
+		return def, nil
+	}
+
+	if p.consumeFold("last") || p.consumeFold("l") {
+		return p.pageCount - p.parseLastOffset(), nil
+	}
+
+	digits := p.pos
+	for p.pos < p.end && isDigit(p.expr[p.pos]) {
+		p.pos++
+	}
+	if digits == p.pos {
+		return 0, p.unexpected()
+	}
+
+	page, err := strconv.Atoi(p.expr[digits:p.pos])
+	if err != nil {
+		return 0, selectionError(p.expr, digits, p.pos-digits, nil, "invalid page number")
+	}
+	return page, nil
+}
+
+// parseLastOffset reads the "-N" in "l-N", if present; otherwise it leaves the position alone
+func (p *selectionParser) parseLastOffset() int {
+	save := p.pos
+	p.skipSpaces()
+	if p.pos < p.end && p.expr[p.pos] == '-' {
+		p.pos++
+		p.skipSpaces()
+		digits := p.pos
+		for p.pos < p.end && isDigit(p.expr[p.pos]) {
+			p.pos++
+		}
+		if offset, err := strconv.Atoi(p.expr[digits:p.pos]); err == nil {
+			return offset
+		}
+	}
+	p.pos = save
+	return 0
+}
+
+// consumeFold advances past word if the input continues with it, ignoring case
+func (p *selectionParser) consumeFold(word string) bool {
+	if p.end-p.pos >= len(word) && strings.EqualFold(p.expr[p.pos:p.pos+len(word)], word) {
+		p.pos += len(word)
+		return true
+	}
+	return false
+}
+
+func (p *selectionParser) skipSpaces() {
+	for p.pos < p.end && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// unexpected reports the character at the current position
+func (p *selectionParser) unexpected() error {
+	r, size := utf8.DecodeRuneInString(p.expr[p.pos:p.end])
+	return selectionError(p.expr, p.pos, size, nil, fmt.Sprintf("unexpected %q", r))
+}
+
+// selectionError builds a PageSelectionError, converting byte offsets to character offsets
+func selectionError(expr string, byteOffset, byteLength int, err error, msg string) *PageSelectionError {
+	return &PageSelectionError{
+		Msg:    msg,
+		Offset: utf8.RuneCountInString(expr[:byteOffset]),
+		Length: utf8.RuneCountInString(expr[byteOffset : byteOffset+byteLength]),
+		Err:    err,
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// removePages drops every occurrence of the given pages
+func removePages(pages, remove []int) []int {
+	removed := make(map[int]bool, len(remove))
+	for _, p := range remove {
+		removed[p] = true
+	}
+
+	kept := pages[:0]
+	for _, p := range pages {
+		if !removed[p] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
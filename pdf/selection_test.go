@@ -0,0 +1,104 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParsePageSelection(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []int
+		wantErr error
+	}{
+		{"5", []int{5}, nil},
+		{"1-3", []int{1, 2, 3}, nil},
+		{"8-", []int{8, 9, 10}, nil},
+		{"-2", []int{1, 2}, nil},
+		{"l", []int{10}, nil},
+		{"last", []int{10}, nil},
+		{"l-2", []int{8}, nil},
+		{"l-2-l", []int{8, 9, 10}, nil},
+		{"9-l", []int{9, 10}, nil},
+		{"odd", []int{1, 3, 5, 7, 9}, nil},
+		{"even", []int{2, 4, 6, 8, 10}, nil},
+		{"1-3,!2", []int{1, 3}, nil},
+		{"1-4, n2-3", []int{1, 4}, nil},
+		{"odd,!1-5", []int{7, 9}, nil},
+		{"!odd", []int{2, 4, 6, 8, 10}, nil},
+		{"1,1,2", []int{1, 1, 2}, nil},
+		{"3,1", []int{3, 1}, nil},
+		{" 1 , L ", []int{1, 10}, nil},
+		{" 2 - 3 ", []int{2, 3}, nil},
+		{"l - 1", []int{9}, nil},
+		{"11", nil, ErrPageOutOfRange},
+		{"0", nil, ErrPageOutOfRange},
+		{"3-2", nil, ErrPageOutOfRange},
+		{"l-10", nil, ErrPageOutOfRange},
+		{"1-3,!1-3", nil, ErrNoPagesSelected},
+		{"", nil, errAny},
+		{"1,,2", nil, errAny},
+		{"!", nil, errAny},
+		{"abc", nil, errAny},
+		{"1-2-3", nil, errAny},
+		{"1x", nil, errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParsePageSelection(tt.expr, 10)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("ParsePageSelection(%q) = %v, want error", tt.expr, got)
+				}
+				if tt.wantErr != errAny && !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParsePageSelection(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePageSelection(%q) error = %v", tt.expr, err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParsePageSelection(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePageSelection_ErrorPosition(t *testing.T) {
+	tests := []struct {
+		expr       string
+		wantOffset int
+		wantLength int
+	}{
+		{"", 0, 0},
+		{"1,,2", 2, 0},
+		{"1-3, 12", 5, 2},
+		{"1x", 1, 1},
+		{"1-2-3", 3, 1},
+		{"odd, !", 5, 1},
+		{"5-2", 0, 3},
+		{"é,1", 0, 1},
+		{"1, é", 3, 1},
+		{"odd,!odd", 0, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParsePageSelection(tt.expr, 10)
+			var selErr *PageSelectionError
+			if !errors.As(err, &selErr) {
+				t.Fatalf("ParsePageSelection(%q) error = %v, want *PageSelectionError", tt.expr, err)
+			}
+			if selErr.Offset != tt.wantOffset || selErr.Length != tt.wantLength {
+				t.Errorf("ParsePageSelection(%q) error at %d+%d, want %d+%d (%v)",
+					tt.expr, selErr.Offset, selErr.Length, tt.wantOffset, tt.wantLength, err)
+			}
+		})
+	}
+}
+
+// errAny marks test cases that expect an error of any kind
+var errAny = errors.New("any error")
@@ -0,0 +1,474 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ExtractPages creates a new PDF containing only the specified pages.
+// Pages are 1-indexed and their order determines the order in the output.
+func ExtractPages(ctx context.Context, path string, pages []int) (*SplitResult, error) {
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages selected")
+	}
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 10,
+		Message: "Preparing extraction...",
+	})
+
+	// Validate page numbers against the document
+	pageCount, err := api.PageCountFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+
+	var pageSelections []string
+	for _, pageNum := range pages {
+		if pageNum < 1 || pageNum > pageCount {
+			return nil, fmt.Errorf("page %d out of range (1-%d)", pageNum, pageCount)
+		}
+		pageSelections = append(pageSelections, fmt.Sprintf("%d", pageNum))
+	}
+
+	safeEmit(ctx, "split:log", fmt.Sprintf("Extracting %d of %d pages", len(pages), pageCount))
+	for _, pageNum := range pages {
+		safeEmit(ctx, "split:log", fmt.Sprintf("Extracting page %d...", pageNum))
+	}
+
+	// Create temp output file
+	outputPath, err := CreateTempFile("extracted", ".pdf")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file: %w", err)
+	}
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 30,
+		Message: "Extracting pages...",
+	})
+
+	// Use pdfcpu to collect the selected pages
+	if err := api.CollectFile(path, outputPath, pageSelections, nil); err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("extraction failed: %w", err)
+	}
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 80,
+		Message: "Finalizing...",
+	})
+
+	// Get output file size
+	outputInfo, err := os.Stat(outputPath)
+	if err != nil {
+		CleanupTempFiles(outputPath)
+		return nil, fmt.Errorf("cannot read output file: %w", err)
+	}
+
+	safeEmit(ctx, "split:log", fmt.Sprintf("Output size: %s", FormatFileSize(outputInfo.Size())))
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return &SplitResult{
+		Success:        true,
+		PagesExtracted: len(pages),
+		OutputSize:     outputInfo.Size(),
+		OutputPath:     outputPath,
+	}, nil
+}
+
+// SplitByRanges writes one PDF per range expression (e.g. "1-3", "4-8", "9-").
+// Each expression is a page selection as accepted by ParsePageSelection.
+func SplitByRanges(ctx context.Context, path string, ranges []string) ([]PDFDocument, error) {
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no ranges specified")
+	}
+
+	pdfCtx, err := readPDFContext(path, model.SPLIT)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+	pageCount := pdfCtx.PageCount
+
+	// Validate all ranges before writing anything
+	var pageRanges [][]int
+	var names []string
+	for _, expr := range ranges {
+		pages, err := ParsePageSelection(expr, pageCount)
+		if err != nil {
+			return nil, err
+		}
+		pageRanges = append(pageRanges, pages)
+		names = append(names, splitPartName(path, selectionLabel(expr, pages)))
+	}
+
+	safeEmit(ctx, "split:log", fmt.Sprintf("Splitting %d pages into %d files", pageCount, len(ranges)))
+
+	return writePageRanges(ctx, pdfCtx, pageRanges, names)
+}
+
+// SplitEveryN writes one PDF per n consecutive pages; the last part may be shorter.
+func SplitEveryN(ctx context.Context, path string, n int) ([]PDFDocument, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("pages per file must be at least 1")
+	}
+
+	pdfCtx, err := readPDFContext(path, model.SPLIT)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+	pageCount := pdfCtx.PageCount
+
+	var pageRanges [][]int
+	var names []string
+	for start := 1; start <= pageCount; start += n {
+		pages := pageSequence(start, min(start+n-1, pageCount))
+		pageRanges = append(pageRanges, pages)
+		names = append(names, splitPartName(path, pageRangeLabel(pages)))
+	}
+
+	safeEmit(ctx, "split:log", fmt.Sprintf("Splitting %d pages every %d pages into %d files", pageCount, n, len(pageRanges)))
+
+	return writePageRanges(ctx, pdfCtx, pageRanges, names)
+}
+
+// SplitBySize greedily packs consecutive pages into parts no larger than maxBytes.
+// Part sizes are measured by writing candidate outputs, not estimated; see largestPartFrom.
+// A single page larger than maxBytes becomes its own part.
+func SplitBySize(ctx context.Context, path string, maxBytes int64) ([]PDFDocument, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("maximum size must be greater than 0")
+	}
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 0,
+		Message: "Reading PDF...",
+	})
+
+	pdfCtx, err := readPDFContext(path, model.SPLIT)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+	pageCount := pdfCtx.PageCount
+
+	safeEmit(ctx, "split:log", fmt.Sprintf("Splitting %d pages into parts under %s", pageCount, FormatFileSize(maxBytes)))
+
+	var documents []PDFDocument
+	var outputPaths []string
+	for start := 1; start <= pageCount; {
+		end, best, err := largestPartFrom(pdfCtx, start, maxBytes)
+		if err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, err
+		}
+		if int64(len(best)) > maxBytes {
+			safeEmit(ctx, "split:log", fmt.Sprintf("Warning: page %d alone is %s, larger than the limit", start, FormatFileSize(int64(len(best)))))
+		}
+
+		outputPath, err := CreateTempFile("split", ".pdf")
+		if err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, fmt.Errorf("cannot create temp file: %w", err)
+		}
+		outputPaths = append(outputPaths, outputPath)
+
+		if err := os.WriteFile(outputPath, best, 0644); err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, fmt.Errorf("cannot write output file: %w", err)
+		}
+
+		doc, err := GetPDFInfo(outputPath)
+		if err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, err
+		}
+		pages := pageSequence(start, end)
+		doc.Name = splitPartName(path, pageRangeLabel(pages))
+		documents = append(documents, *doc)
+
+		safeEmit(ctx, "split:log", fmt.Sprintf("Part %d: pages %s (%s)", len(documents), pageRangeLabel(pages), doc.SizeText))
+		safeEmit(ctx, "split:progress", ProgressUpdate{
+			Percent: end * 100 / pageCount,
+			Message: fmt.Sprintf("Wrote part %d...", len(documents)),
+		})
+
+		start = end + 1
+	}
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return documents, nil
+}
+
+// largestPartFrom finds the longest run of pages from start whose output fits in maxBytes,
+// returning its last page and the rendered bytes. The run doubles in length until it no
+// longer fits, then a binary search narrows down the boundary, so a part of n pages costs
+// O(log n) writes. A first page that doesn't fit on its own is returned as a part of one.
+func largestPartFrom(pdfCtx *model.Context, start int, maxBytes int64) (int, []byte, error) {
+	pageCount := pdfCtx.PageCount
+
+	render := func(end int) ([]byte, bool, error) {
+		data, err := renderPages(pdfCtx, pageSequence(start, end))
+		if err != nil {
+			return nil, false, fmt.Errorf("split failed at pages %d-%d: %w", start, end, err)
+		}
+		return data, int64(len(data)) <= maxBytes, nil
+	}
+
+	best, fits, err := render(start)
+	if err != nil || !fits {
+		return start, best, err
+	}
+
+	// fit is the last page known to fit, tooBig the first known not to (pageCount+1 if none)
+	fit, tooBig := start, pageCount+1
+	for step := 1; fit < pageCount; step *= 2 {
+		end := min(start+step, pageCount)
+		data, fits, err := render(end)
+		if err != nil {
+			return 0, nil, err
+		}
+		if !fits {
+			tooBig = end
+			break
+		}
+		fit, best = end, data
+	}
+
+	for tooBig-fit > 1 {
+		mid := (fit + tooBig) / 2
+		data, fits, err := render(mid)
+		if err != nil {
+			return 0, nil, err
+		}
+		if fits {
+			fit, best = mid, data
+		} else {
+			tooBig = mid
+		}
+	}
+	return fit, best, nil
+}
+
+// SplitByBookmarks writes one PDF per outline entry at the given depth (1 = top level).
+// Each section runs until the next one starts; pages before the first bookmark
+// become a "Front matter" part. Document names are suggested file names
+// derived from the bookmark titles.
+func SplitByBookmarks(ctx context.Context, path string, level int) ([]PDFDocument, error) {
+	if level < 1 {
+		return nil, fmt.Errorf("bookmark level must be at least 1")
+	}
+
+	pdfCtx, err := readPDFContext(path, model.SPLIT)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read PDF: %w", err)
+	}
+	pageCount := pdfCtx.PageCount
+
+	bookmarks, err := pdfcpu.Bookmarks(pdfCtx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read bookmarks: %w", err)
+	}
+
+	var sections []bookmarkSection
+	for _, section := range collectBookmarkSections(bookmarks, 1, level) {
+		if section.pageFrom >= 1 && section.pageFrom <= pageCount {
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("PDF has no bookmarks")
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].pageFrom < sections[j].pageFrom
+	})
+	if sections[0].pageFrom > 1 {
+		sections = append([]bookmarkSection{{title: "Front matter", pageFrom: 1}}, sections...)
+	}
+
+	var pageRanges [][]int
+	var names []string
+	usedNames := make(map[string]int)
+	for i, section := range sections {
+		end := pageCount
+		if i+1 < len(sections) {
+			end = sections[i+1].pageFrom - 1
+		}
+		if end < section.pageFrom {
+			// Starts on the same page as the next bookmark, so it has no pages of its own
+			continue
+		}
+
+		pages := pageSequence(section.pageFrom, end)
+		safeEmit(ctx, "split:log", fmt.Sprintf("Section %q: pages %s", section.title, pageRangeLabel(pages)))
+
+		name := SanitizeFileName(section.title)
+		if name == "" {
+			name = fmt.Sprintf("section_%d", len(names)+1)
+		}
+		usedNames[strings.ToLower(name)]++
+		if n := usedNames[strings.ToLower(name)]; n > 1 {
+			name = fmt.Sprintf("%s (%d)", name, n)
+		}
+
+		pageRanges = append(pageRanges, pages)
+		names = append(names, name+".pdf")
+	}
+
+	safeEmit(ctx, "split:log", fmt.Sprintf("Splitting %d pages into %d sections", pageCount, len(pageRanges)))
+
+	return writePageRanges(ctx, pdfCtx, pageRanges, names)
+}
+
+// bookmarkSection is an outline entry used as a split point
+type bookmarkSection struct {
+	title    string
+	pageFrom int
+}
+
+// collectBookmarkSections flattens the outline down to the given level.
+// Entries above that level are kept alongside their children so that pages
+// before the first child stay with the parent; entries without children
+// stand in for the missing deeper levels.
+func collectBookmarkSections(bookmarks []pdfcpu.Bookmark, depth, level int) []bookmarkSection {
+	var sections []bookmarkSection
+	for _, bm := range bookmarks {
+		sections = append(sections, bookmarkSection{title: bm.Title, pageFrom: bm.PageFrom})
+		if depth < level && len(bm.Kids) > 0 {
+			sections = append(sections, collectBookmarkSections(bm.Kids, depth+1, level)...)
+		}
+	}
+	return sections
+}
+
+// writePageRanges writes each page range to its own temp file, named by the matching entry in names.
+// If any part fails, all parts written so far are removed.
+func writePageRanges(ctx context.Context, pdfCtx *model.Context, pageRanges [][]int, names []string) ([]PDFDocument, error) {
+	var documents []PDFDocument
+	var outputPaths []string
+	for i, pages := range pageRanges {
+		safeEmit(ctx, "split:progress", ProgressUpdate{
+			Percent: i * 100 / len(pageRanges),
+			Message: fmt.Sprintf("Writing part %d of %d...", i+1, len(pageRanges)),
+		})
+
+		label := pageRangeLabel(pages)
+		safeEmit(ctx, "split:log", fmt.Sprintf("Extracting pages %s", label))
+
+		outputPath, err := CreateTempFile("split", ".pdf")
+		if err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, fmt.Errorf("cannot create temp file: %w", err)
+		}
+		outputPaths = append(outputPaths, outputPath)
+
+		data, err := renderPages(pdfCtx, pages)
+		if err == nil {
+			err = os.WriteFile(outputPath, data, 0644)
+		}
+		if err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, fmt.Errorf("split failed for pages %s: %w", label, err)
+		}
+
+		doc, err := GetPDFInfo(outputPath)
+		if err != nil {
+			CleanupTempFiles(outputPaths...)
+			return nil, err
+		}
+		doc.Name = names[i]
+		documents = append(documents, *doc)
+	}
+
+	safeEmit(ctx, "split:progress", ProgressUpdate{
+		Percent: 100,
+		Message: "Complete",
+	})
+
+	return documents, nil
+}
+
+// renderPages builds a new PDF from the given pages and returns its bytes
+func renderPages(pdfCtx *model.Context, pages []int) ([]byte, error) {
+	ctxNew, err := pdfcpu.ExtractPages(pdfCtx, pages, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := api.WriteContext(ctxNew, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pageSequence returns the page numbers from start through end inclusive
+func pageSequence(start, end int) []int {
+	pages := make([]int, 0, end-start+1)
+	for p := start; p <= end; p++ {
+		pages = append(pages, p)
+	}
+	return pages
+}
+
+// splitPartName suggests a file name for a part, e.g. "report_pages_1-3.pdf"
+func splitPartName(path, label string) string {
+	baseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return fmt.Sprintf("%s_pages_%s.pdf", baseName, label)
+}
+
+// selectionLabel names the pages chosen by a selection expression: "1-3" when they are
+// consecutive, otherwise the expression itself (e.g. "odd" or "2,7"), made safe for file names
+func selectionLabel(expr string, pages []int) string {
+	if isPageRun(pages) {
+		return pageRangeLabel(pages)
+	}
+	if label := SanitizeFileName(strings.Join(strings.Fields(expr), "")); label != "" {
+		return label
+	}
+	return pageRangeLabel(pages)
+}
+
+// pageRangeLabel formats pages for file names and logs, collapsing runs: "3", "1-3" or "1-3,5,9"
+func pageRangeLabel(pages []int) string {
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, fmt.Sprintf("%d", pages[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// isPageRun reports whether pages is a single ascending run of consecutive pages
+func isPageRun(pages []int) bool {
+	for i := 1; i < len(pages); i++ {
+		if pages[i] != pages[i-1]+1 {
+			return false
+		}
+	}
+	return len(pages) > 0
+}
@@ -0,0 +1,433 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func TestExtractPages_Selection(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	if originalPages < 5 {
+		t.Skip("Need at least 5 pages for this test")
+	}
+
+	result, err := ExtractPages(mockContext(), fixture, []int{1, 3, 5})
+	if err != nil {
+		t.Fatalf("ExtractPages() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	if !result.Success {
+		t.Error("ExtractPages().Success = false, want true")
+	}
+
+	if result.PagesExtracted != 3 {
+		t.Errorf("ExtractPages().PagesExtracted = %d, want 3", result.PagesExtracted)
+	}
+
+	if result.OutputSize == 0 {
+		t.Error("ExtractPages().OutputSize should not be 0")
+	}
+
+	// Verify output is valid PDF
+	if err := api.ValidateFile(result.OutputPath, nil); err != nil {
+		t.Errorf("Output is not valid PDF: %v", err)
+	}
+
+	outputPages, _ := api.PageCountFile(result.OutputPath)
+	if outputPages != 3 {
+		t.Errorf("Output page count = %d, want 3", outputPages)
+	}
+}
+
+func TestExtractPages_CustomOrder(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	if originalPages < 2 {
+		t.Skip("Need at least 2 pages for this test")
+	}
+
+	result, err := ExtractPages(mockContext(), fixture, []int{2, 1})
+	if err != nil {
+		t.Fatalf("ExtractPages() error = %v", err)
+	}
+	defer CleanupTempFiles(result.OutputPath)
+
+	outputPages, _ := api.PageCountFile(result.OutputPath)
+	if outputPages != 2 {
+		t.Errorf("Output page count = %d, want 2", outputPages)
+	}
+}
+
+func TestExtractPages_EmptySelection(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	_, err := ExtractPages(mockContext(), fixture, []int{})
+	if err == nil {
+		t.Error("ExtractPages() should return error for empty selection")
+	}
+}
+
+func TestExtractPages_OutOfRange(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	for _, page := range []int{0, 2, -1} {
+		if _, err := ExtractPages(mockContext(), fixture, []int{page}); err == nil {
+			t.Errorf("ExtractPages() should return error for page %d", page)
+		}
+	}
+}
+
+func TestExtractPages_InvalidFile(t *testing.T) {
+	_, err := ExtractPages(mockContext(), "/nonexistent/file.pdf", []int{1})
+	if err == nil {
+		t.Error("ExtractPages() should return error for non-existent file")
+	}
+}
+
+func TestSplitByRanges(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	if originalPages < 5 {
+		t.Skip("Need at least 5 pages for this test")
+	}
+
+	docs, err := SplitByRanges(mockContext(), fixture, []string{"1-2", "3", "4-"})
+	if err != nil {
+		t.Fatalf("SplitByRanges() error = %v", err)
+	}
+	for _, doc := range docs {
+		defer CleanupTempFiles(doc.Path)
+	}
+
+	if len(docs) != 3 {
+		t.Fatalf("SplitByRanges() returned %d documents, want 3", len(docs))
+	}
+
+	expected := []int{2, 1, originalPages - 3}
+	for i, doc := range docs {
+		if doc.PageCount != expected[i] {
+			t.Errorf("Part %d page count = %d, want %d", i+1, doc.PageCount, expected[i])
+		}
+		if err := api.ValidateFile(doc.Path, nil); err != nil {
+			t.Errorf("Part %d is not valid PDF: %v", i+1, err)
+		}
+	}
+
+	if docs[0].Name != "multi-page_pages_1-2.pdf" {
+		t.Errorf("Part 1 name = %q, want %q", docs[0].Name, "multi-page_pages_1-2.pdf")
+	}
+}
+
+func TestSelectionLabel(t *testing.T) {
+	tests := []struct {
+		expr  string
+		pages []int
+		want  string
+	}{
+		{"3", []int{3}, "3"},
+		{"9-", []int{9, 10}, "9-10"},
+		{"odd", []int{1, 3, 5, 7, 9}, "odd"},
+		{"2, 7", []int{2, 7}, "2,7"},
+		{"1-3,5", []int{1, 2, 3, 5}, "1-3,5"},
+		{"5-3", []int{5, 4, 3}, "5-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := selectionLabel(tt.expr, tt.pages); got != tt.want {
+				t.Errorf("selectionLabel(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageRangeLabel(t *testing.T) {
+	tests := []struct {
+		pages []int
+		want  string
+	}{
+		{[]int{3}, "3"},
+		{[]int{1, 2, 3}, "1-3"},
+		{[]int{2, 7}, "2,7"},
+		{[]int{1, 2, 3, 5, 8, 9}, "1-3,5,8-9"},
+		{[]int{3, 2, 1}, "3,2,1"},
+	}
+
+	for _, tt := range tests {
+		if got := pageRangeLabel(tt.pages); got != tt.want {
+			t.Errorf("pageRangeLabel(%v) = %q, want %q", tt.pages, got, tt.want)
+		}
+	}
+}
+
+func TestSplitByRanges_InvalidRange(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	for _, expr := range []string{"", "abc", "3-1", "0-2", "1-999"} {
+		if _, err := SplitByRanges(mockContext(), fixture, []string{expr}); err == nil {
+			t.Errorf("SplitByRanges() should return error for range %q", expr)
+		}
+	}
+}
+
+func TestSplitEveryN(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	docs, err := SplitEveryN(mockContext(), fixture, 3)
+	if err != nil {
+		t.Fatalf("SplitEveryN() error = %v", err)
+	}
+	for _, doc := range docs {
+		defer CleanupTempFiles(doc.Path)
+	}
+
+	expectedParts := (originalPages + 2) / 3
+	if len(docs) != expectedParts {
+		t.Fatalf("SplitEveryN() returned %d documents, want %d", len(docs), expectedParts)
+	}
+
+	totalPages := 0
+	for i, doc := range docs {
+		if i < len(docs)-1 && doc.PageCount != 3 {
+			t.Errorf("Part %d page count = %d, want 3", i+1, doc.PageCount)
+		}
+		totalPages += doc.PageCount
+	}
+	if totalPages != originalPages {
+		t.Errorf("Total page count = %d, want %d", totalPages, originalPages)
+	}
+}
+
+func TestSplitEveryN_Invalid(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := SplitEveryN(mockContext(), fixture, 0); err == nil {
+		t.Error("SplitEveryN() should return error for n = 0")
+	}
+}
+
+func TestSplitBySize(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	info, err := os.Stat(fixture)
+	if err != nil {
+		t.Fatalf("Failed to stat fixture: %v", err)
+	}
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	// Aim for roughly three parts
+	maxBytes := info.Size() / 3
+	docs, err := SplitBySize(mockContext(), fixture, maxBytes)
+	if err != nil {
+		t.Fatalf("SplitBySize() error = %v", err)
+	}
+	for _, doc := range docs {
+		defer CleanupTempFiles(doc.Path)
+	}
+
+	if len(docs) < 2 {
+		t.Errorf("SplitBySize() returned %d documents, want at least 2", len(docs))
+	}
+
+	totalPages := 0
+	for i, doc := range docs {
+		if doc.Size > maxBytes && doc.PageCount > 1 {
+			t.Errorf("Part %d is %d bytes, over the %d byte limit", i+1, doc.Size, maxBytes)
+		}
+		totalPages += doc.PageCount
+	}
+	if totalPages != originalPages {
+		t.Errorf("Total page count = %d, want %d", totalPages, originalPages)
+	}
+}
+
+func TestSplitBySize_LimitSmallerThanPage(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	// Every page exceeds the limit, so each becomes its own part
+	docs, err := SplitBySize(mockContext(), fixture, 1)
+	if err != nil {
+		t.Fatalf("SplitBySize() error = %v", err)
+	}
+	for _, doc := range docs {
+		defer CleanupTempFiles(doc.Path)
+	}
+
+	if len(docs) != originalPages {
+		t.Errorf("SplitBySize() returned %d documents, want %d", len(docs), originalPages)
+	}
+}
+
+func TestSplitBySize_WholeFileFits(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	docs, err := SplitBySize(mockContext(), fixture, 1<<30)
+	if err != nil {
+		t.Fatalf("SplitBySize() error = %v", err)
+	}
+	for _, doc := range docs {
+		defer CleanupTempFiles(doc.Path)
+	}
+
+	if len(docs) != 1 || docs[0].PageCount != originalPages {
+		t.Errorf("SplitBySize() returned %d documents, want one with all %d pages", len(docs), originalPages)
+	}
+}
+
+func TestSplitBySize_Invalid(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := SplitBySize(mockContext(), fixture, 0); err == nil {
+		t.Error("SplitBySize() should return error for zero size")
+	}
+}
+
+func TestSplitByBookmarks(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "multi-page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	originalPages, err := api.PageCountFile(fixture)
+	if err != nil {
+		t.Fatalf("Failed to get page count: %v", err)
+	}
+
+	if originalPages < 7 {
+		t.Skip("Need at least 7 pages for this test")
+	}
+
+	// Create a bookmarked copy of the fixture
+	bookmarked := filepath.Join(t.TempDir(), "bookmarked.pdf")
+	bookmarks := []pdfcpu.Bookmark{
+		{Title: "Intro", PageFrom: 2},
+		{Title: "Chapter: One", PageFrom: 3, Kids: []pdfcpu.Bookmark{
+			{Title: "Part A", PageFrom: 4},
+		}},
+		{Title: "Chapter 2", PageFrom: 6},
+	}
+	if err := api.AddBookmarksFile(fixture, bookmarked, bookmarks, true, nil); err != nil {
+		t.Fatalf("Failed to add bookmarks: %v", err)
+	}
+
+	tests := []struct {
+		level int
+		names []string
+		pages []int
+	}{
+		{1, []string{"Front matter.pdf", "Intro.pdf", "Chapter_ One.pdf", "Chapter 2.pdf"}, []int{1, 1, 3, originalPages - 5}},
+		{2, []string{"Front matter.pdf", "Intro.pdf", "Chapter_ One.pdf", "Part A.pdf", "Chapter 2.pdf"}, []int{1, 1, 1, 2, originalPages - 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("level%d", tt.level), func(t *testing.T) {
+			docs, err := SplitByBookmarks(mockContext(), bookmarked, tt.level)
+			if err != nil {
+				t.Fatalf("SplitByBookmarks() error = %v", err)
+			}
+			for _, doc := range docs {
+				defer CleanupTempFiles(doc.Path)
+			}
+
+			if len(docs) != len(tt.names) {
+				t.Fatalf("SplitByBookmarks() returned %d documents, want %d", len(docs), len(tt.names))
+			}
+
+			for i, doc := range docs {
+				if doc.Name != tt.names[i] {
+					t.Errorf("Part %d name = %q, want %q", i+1, doc.Name, tt.names[i])
+				}
+				if doc.PageCount != tt.pages[i] {
+					t.Errorf("Part %d page count = %d, want %d", i+1, doc.PageCount, tt.pages[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitByBookmarks_NoBookmarks(t *testing.T) {
+	fixture := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("Fixture not found. Run 'go run test/download_fixtures.go' first")
+	}
+
+	if _, err := SplitByBookmarks(mockContext(), fixture, 1); err == nil {
+		t.Error("SplitByBookmarks() should return error for PDF without bookmarks")
+	}
+}
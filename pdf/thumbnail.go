@@ -7,6 +7,8 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -133,6 +135,60 @@ func GenerateAllThumbnails(ctx context.Context, pdfPath string, width, height in
 
 // GenerateThumbnail generates a thumbnail for a single page
 func GenerateThumbnail(ctx context.Context, pdfPath string, pageIndex int, width, height int) (*ThumbnailResult, error) {
+	data, err := generateThumbnailPNG(ctx, pdfPath, pageIndex, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThumbnailResult{
+		PageIndex: pageIndex,
+		ImageData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(data),
+		Width:     width,
+		Height:    height,
+	}, nil
+}
+
+// GenerateRotatedThumbnail previews a page with a rotation applied, without touching the PDF.
+// The cached thumbnail is rotated in Go, so Ghostscript only runs on a cache miss.
+func GenerateRotatedThumbnail(ctx context.Context, pdfPath string, pageIndex int, width, height int, rotation RotationAngle) (*ThumbnailResult, error) {
+	if rotation%90 != 0 {
+		return nil, fmt.Errorf("invalid rotation %d: must be a multiple of 90", rotation)
+	}
+	angle := normalizeRotation(int(rotation))
+
+	data, err := generateThumbnailPNG(ctx, pdfPath, pageIndex, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if angle != 0 {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode thumbnail: %w", err)
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, rotateImage(img, angle)); err != nil {
+			return nil, fmt.Errorf("cannot encode thumbnail: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	// Quarter turns swap the thumbnail's dimensions
+	if angle == 90 || angle == 270 {
+		width, height = height, width
+	}
+
+	return &ThumbnailResult{
+		PageIndex: pageIndex,
+		ImageData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(data),
+		Width:     width,
+		Height:    height,
+	}, nil
+}
+
+// generateThumbnailPNG returns the PNG bytes for a page, rendering it into the cache if needed
+func generateThumbnailPNG(ctx context.Context, pdfPath string, pageIndex int, width, height int) ([]byte, error) {
 	// Get page count to validate index
 	pageCount, err := getPageCount(pdfPath)
 	if err != nil {
@@ -148,12 +204,7 @@ func GenerateThumbnail(ctx context.Context, pdfPath string, pageIndex int, width
 	cachePath := filepath.Join(cacheDir, fmt.Sprintf("page_%03d.png", pageIndex+1))
 
 	if data, err := os.ReadFile(cachePath); err == nil {
-		return &ThumbnailResult{
-			PageIndex: pageIndex,
-			ImageData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(data),
-			Width:     width,
-			Height:    height,
-		}, nil
+		return data, nil
 	}
 
 	// Generate just this page
@@ -171,8 +222,21 @@ func GenerateThumbnail(ctx context.Context, pdfPath string, pageIndex int, width
 	}
 
 	// GS uses 1-based page numbers
-	gsPageNum := pageIndex + 1
+	if err := renderPagePNG(ctx, gsPath, pdfPath, pageIndex+1, width, height, cachePath); err != nil {
+		return nil, err
+	}
+
+	// Read the generated file
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read generated thumbnail: %w", err)
+	}
 
+	return data, nil
+}
+
+// renderPagePNG renders one page (1-based) to a PNG file, scaled to fit width × height
+func renderPagePNG(ctx context.Context, gsPath, pdfPath string, pageNum, width, height int, outputPath string) error {
 	args := []string{
 		"-dSAFER",
 		"-dNOPAUSE",
@@ -183,9 +247,9 @@ func GenerateThumbnail(ctx context.Context, pdfPath string, pageIndex int, width
 		"-dPDFFitPage",
 		"-dTextAlphaBits=4",
 		"-dGraphicsAlphaBits=4",
-		fmt.Sprintf("-dFirstPage=%d", gsPageNum),
-		fmt.Sprintf("-dLastPage=%d", gsPageNum),
-		fmt.Sprintf("-sOutputFile=%s", cachePath),
+		fmt.Sprintf("-dFirstPage=%d", pageNum),
+		fmt.Sprintf("-dLastPage=%d", pageNum),
+		fmt.Sprintf("-sOutputFile=%s", outputPath),
 		pdfPath,
 	}
 
@@ -196,27 +260,44 @@ func GenerateThumbnail(ctx context.Context, pdfPath string, pageIndex int, width
 
 	if err := cmd.Run(); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("thumbnail generation timed out")
+			return fmt.Errorf("thumbnail generation timed out")
 		}
 		errMsg := stderr.String()
 		if errMsg != "" {
-			return nil, fmt.Errorf("ghostscript failed: %s", errMsg)
+			return fmt.Errorf("ghostscript failed: %s", errMsg)
 		}
-		return nil, fmt.Errorf("ghostscript failed: %w", err)
+		return fmt.Errorf("ghostscript failed: %w", err)
 	}
+	return nil
+}
 
-	// Read the generated file
-	data, err := os.ReadFile(cachePath)
-	if err != nil {
-		return nil, fmt.Errorf("cannot read generated thumbnail: %w", err)
+// rotateImage rotates an image clockwise by 90, 180 or 270 degrees
+func rotateImage(src image.Image, angle int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	var dst *image.NRGBA
+	if angle == 180 {
+		dst = image.NewNRGBA(image.Rect(0, 0, w, h))
+	} else {
+		dst = image.NewNRGBA(image.Rect(0, 0, h, w))
 	}
 
-	return &ThumbnailResult{
-		PageIndex: pageIndex,
-		ImageData: "data:image/png;base64," + base64.StdEncoding.EncodeToString(data),
-		Width:     width,
-		Height:    height,
-	}, nil
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := src.At(b.Min.X+x, b.Min.Y+y)
+			switch angle {
+			case 90:
+				dst.Set(h-1-y, x, c)
+			case 180:
+				dst.Set(w-1-x, h-1-y, c)
+			case 270:
+				dst.Set(y, w-1-x, c)
+			}
+		}
+	}
+
+	return dst
 }
 
 // getThumbnailCacheDir returns a unique cache directory for a PDF
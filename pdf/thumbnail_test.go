@@ -1,7 +1,12 @@
 package pdf
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
 	"os"
 	"path/filepath"
 	"strings"
@@ -110,6 +115,121 @@ func TestGenerateThumbnail_InvalidPageIndex(t *testing.T) {
 	}
 }
 
+func TestGenerateRotatedThumbnail_FromCache(t *testing.T) {
+	ctx := context.Background()
+
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Test fixture not found")
+	}
+
+	// Work on a copy so the seeded cache entry doesn't leak into other tests
+	data, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdfPath := filepath.Join(t.TempDir(), "rotate.pdf")
+	if err := os.WriteFile(pdfPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer CleanupThumbnailCache(pdfPath)
+
+	// Seed the cache with a 2x3 image so no Ghostscript run is needed
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 3))
+	src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := getThumbnailCacheDir(pdfPath)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "page_001.png"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rotation    RotationAngle
+		width       int
+		height      int
+		imageWidth  int
+		imageHeight int
+	}{
+		{Rotate0, 2, 3, 2, 3},
+		{Rotate90, 3, 2, 3, 2},
+		{Rotate180, 2, 3, 2, 3},
+		{Rotate270, 3, 2, 3, 2},
+	}
+
+	for _, tt := range tests {
+		result, err := GenerateRotatedThumbnail(ctx, pdfPath, 0, 2, 3, tt.rotation)
+		if err != nil {
+			t.Fatalf("GenerateRotatedThumbnail(%d) failed: %v", tt.rotation, err)
+		}
+
+		if result.Width != tt.width || result.Height != tt.height {
+			t.Errorf("Rotation %d: expected %dx%d, got %dx%d", tt.rotation, tt.width, tt.height, result.Width, result.Height)
+		}
+
+		encoded := strings.TrimPrefix(result.ImageData, "data:image/png;base64,")
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			t.Fatalf("Rotation %d: invalid base64: %v", tt.rotation, err)
+		}
+		img, err := png.Decode(bytes.NewReader(decoded))
+		if err != nil {
+			t.Fatalf("Rotation %d: invalid PNG: %v", tt.rotation, err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.imageWidth || b.Dy() != tt.imageHeight {
+			t.Errorf("Rotation %d: image is %dx%d, want %dx%d", tt.rotation, b.Dx(), b.Dy(), tt.imageWidth, tt.imageHeight)
+		}
+	}
+}
+
+func TestGenerateRotatedThumbnail_InvalidRotation(t *testing.T) {
+	ctx := context.Background()
+
+	fixturePath := filepath.Join("..", "test", "fixtures", "valid", "simple-1page.pdf")
+	if _, err := os.Stat(fixturePath); os.IsNotExist(err) {
+		t.Skip("Test fixture not found")
+	}
+
+	_, err := GenerateRotatedThumbnail(ctx, fixturePath, 0, 100, 140, 45)
+	if err == nil {
+		t.Error("Expected error for invalid rotation")
+	}
+}
+
+func TestRotateImage(t *testing.T) {
+	// 2x1 image: red on the left, blue on the right
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	src.Set(0, 0, red)
+	src.Set(1, 0, blue)
+
+	tests := []struct {
+		angle int
+		red   image.Point
+		blue  image.Point
+	}{
+		{90, image.Pt(0, 0), image.Pt(0, 1)},
+		{180, image.Pt(1, 0), image.Pt(0, 0)},
+		{270, image.Pt(0, 1), image.Pt(0, 0)},
+	}
+
+	for _, tt := range tests {
+		dst := rotateImage(src, tt.angle)
+		if got := color.NRGBAModel.Convert(dst.At(tt.red.X, tt.red.Y)); got != red {
+			t.Errorf("rotateImage(%d): expected red at %v, got %v", tt.angle, tt.red, got)
+		}
+		if got := color.NRGBAModel.Convert(dst.At(tt.blue.X, tt.blue.Y)); got != blue {
+			t.Errorf("rotateImage(%d): expected blue at %v, got %v", tt.angle, tt.blue, got)
+		}
+	}
+}
+
 func TestGenerateAllThumbnails_InvalidFile(t *testing.T) {
 	ctx := context.Background()
 
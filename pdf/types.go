@@ -28,16 +28,142 @@ const (
 	PresetPrinter  CompressionPreset = "printer"
 	PresetPrepress CompressionPreset = "prepress"
 	PresetDefault  CompressionPreset = "default"
+	PresetLossless CompressionPreset = "lossless" // pdfcpu optimization only; no Ghostscript, no quality loss
 )
 
+// CompressionOptions is a Ghostscript compression profile. Zero resolutions, threshold
+// and quality leave the setting to the PDFSettings base profile, and an empty
+// CompatibilityLevel means "1.4"; SubsetFonts is always passed as given.
+type CompressionOptions struct {
+	Description          string  `json:"description,omitempty"`
+	PDFSettings          string  `json:"pdfSettings"`          // base profile: screen, ebook, printer, prepress or default
+	ColorImageResolution int     `json:"colorImageResolution"` // dpi to downsample color images to
+	GrayImageResolution  int     `json:"grayImageResolution"`  // dpi to downsample grayscale images to
+	MonoImageResolution  int     `json:"monoImageResolution"`  // dpi to downsample black-and-white images to
+	DownsampleThreshold  float64 `json:"downsampleThreshold"`  // only downsample images above resolution × threshold (>= 1.0)
+	JPEGQuality          int     `json:"jpegQuality"`          // 1-100; forces JPEG for color and gray images
+	CompatibilityLevel   string  `json:"compatibilityLevel"`   // output PDF version, e.g. "1.4"
+	SubsetFonts          bool    `json:"subsetFonts"`          // embed only the glyphs used
+	StripMetadata        bool    `json:"stripMetadata"`        // drop document info (title, author, ...) and XMP metadata
+	SkipIfLarger         bool    `json:"skipIfLarger"`         // never return a file bigger than the original
+}
+
 // CompressionResult holds the result of a compression operation
 type CompressionResult struct {
-	Success        bool   `json:"success"`
-	OriginalSize   int64  `json:"originalSize"`
-	CompressedSize int64  `json:"compressedSize"`
-	SavingsPercent int    `json:"savingsPercent"`
-	OutputPath     string `json:"outputPath"`
-	Error          string `json:"error,omitempty"`
+	Success        bool                `json:"success"`
+	OriginalSize   int64               `json:"originalSize"`
+	CompressedSize int64               `json:"compressedSize"`
+	SavingsPercent int                 `json:"savingsPercent"`
+	OutputPath     string              `json:"outputPath"`
+	Method         CompressionMethod   `json:"method,omitempty"`
+	Preset         CompressionPreset   `json:"preset,omitempty"`
+	Optimization   *OptimizationReport `json:"optimization,omitempty"` // set when Method is MethodOptimize
+	Error          string              `json:"error,omitempty"`
+}
+
+// OptimizationReport describes what a lossless optimization removed
+type OptimizationReport struct {
+	DuplicateFonts    int `json:"duplicateFonts"`    // embedded fonts replaced by an identical copy
+	DuplicateImages   int `json:"duplicateImages"`   // images replaced by an identical copy
+	RemovedObjects    int `json:"removedObjects"`    // objects dropped in total, including unused resources
+	CompressedStreams int `json:"compressedStreams"` // uncompressed streams that are now Flate-compressed
+}
+
+// CompressionMethod records how a CompressionResult's output was produced
+type CompressionMethod string
+
+const (
+	MethodGhostscript CompressionMethod = "ghostscript" // Ghostscript rewrite
+	MethodOptimize    CompressionMethod = "optimize"    // lossless pdfcpu optimization
+	MethodOriginal    CompressionMethod = "original"    // unchanged copy; nothing made it smaller
+)
+
+// BatchCompressionResult holds the outcome of compressing several files with CompressBatch
+type BatchCompressionResult struct {
+	Files               []BatchFileResult `json:"files"` // in input order
+	Succeeded           int               `json:"succeeded"`
+	Failed              int               `json:"failed"`
+	TotalOriginalSize   int64             `json:"totalOriginalSize"`   // of the files that succeeded
+	TotalCompressedSize int64             `json:"totalCompressedSize"` // of the files that succeeded
+	SavingsPercent      int               `json:"savingsPercent"`
+}
+
+// BatchFileResult is one file's outcome in a batch; exactly one of Result and Error is set
+type BatchFileResult struct {
+	ID     string             `json:"id"` // matches FileID in BatchFileProgress events
+	Path   string             `json:"path"`
+	Result *CompressionResult `json:"result,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// BatchFileProgress is a progress event for one file in a batch
+type BatchFileProgress struct {
+	FileID  string `json:"fileId"`
+	Path    string `json:"path"`
+	Percent int    `json:"percent"`
+	Message string `json:"message"`
+	Done    bool   `json:"done"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CompressionPreview shows one page before and after compression with a preset
+type CompressionPreview struct {
+	PageIndex          int               `json:"pageIndex"` // 0-based page index
+	Preset             CompressionPreset `json:"preset"`
+	Original           *ThumbnailResult  `json:"original"`
+	Compressed         *ThumbnailResult  `json:"compressed"`
+	OriginalPageSize   int64             `json:"originalPageSize"`   // the page's share of the original file
+	CompressedPageSize int64             `json:"compressedPageSize"` // the page after compression
+	SizeRatio          float64           `json:"sizeRatio"`          // compressed / original for this page
+	EstimatedSize      int64             `json:"estimatedSize"`      // whole file size if every page compressed alike
+}
+
+// PDFAnalysis breaks down what is taking up space in a PDF
+type PDFAnalysis struct {
+	FileSize          int64             `json:"fileSize"`
+	PageCount         int               `json:"pageCount"`
+	Categories        []SizeCategory    `json:"categories"`     // images, fonts, content, attachments, metadata, other
+	Images            []ImageAnalysis   `json:"images"`         // largest first
+	Fonts             []FontAnalysis    `json:"fonts"`          // embedded fonts, largest first
+	LargestObjects    []ObjectSize      `json:"largestObjects"` // the biggest streams in the file
+	RecommendedPreset CompressionPreset `json:"recommendedPreset"`
+	Recommendation    string            `json:"recommendation"` // why the preset was recommended
+}
+
+// SizeCategory is one slice of a PDFAnalysis byte breakdown
+type SizeCategory struct {
+	Name    string `json:"name"`
+	Bytes   int64  `json:"bytes"`
+	Count   int    `json:"count"`   // number of objects; 0 for "other"
+	Percent int    `json:"percent"` // share of the file size
+}
+
+// ImageAnalysis describes one image in a PDFAnalysis
+type ImageAnalysis struct {
+	ObjectNumber int    `json:"objectNumber"`
+	Page         int    `json:"page"` // first page showing the image, 0 if none does
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	DPI          int    `json:"dpi"`    // effective resolution where first drawn; 0 if not drawn on a page
+	Filter       string `json:"filter"` // e.g. DCTDecode (JPEG), FlateDecode, JPXDecode (JPEG 2000)
+	ColorSpace   string `json:"colorSpace"`
+	Bytes        int64  `json:"bytes"`
+}
+
+// FontAnalysis describes one embedded font program in a PDFAnalysis
+type FontAnalysis struct {
+	ObjectNumber int    `json:"objectNumber"`
+	Name         string `json:"name"`
+	Format       string `json:"format"` // Type1, TrueType or CFF/OpenType
+	Bytes        int64  `json:"bytes"`
+}
+
+// ObjectSize is one entry in PDFAnalysis.LargestObjects
+type ObjectSize struct {
+	ObjectNumber int    `json:"objectNumber"`
+	Category     string `json:"category"`
+	Description  string `json:"description"`
+	Bytes        int64  `json:"bytes"`
 }
 
 // CombineResult holds the result of a combine operation
@@ -50,12 +176,85 @@ type CombineResult struct {
 	Error      string `json:"error,omitempty"`
 }
 
+// SplitResult holds the result of a page extraction
+type SplitResult struct {
+	Success        bool   `json:"success"`
+	PagesExtracted int    `json:"pagesExtracted"`
+	OutputSize     int64  `json:"outputSize"`
+	OutputPath     string `json:"outputPath"`
+	Error          string `json:"error,omitempty"`
+}
+
+// RotationAngle is a clockwise page rotation in degrees
+type RotationAngle int
+
+const (
+	Rotate0   RotationAngle = 0 // no rotation / reset
+	Rotate90  RotationAngle = 90
+	Rotate180 RotationAngle = 180
+	Rotate270 RotationAngle = 270
+)
+
+// PageRotation pairs a 1-indexed page with the rotation to apply to it
+type PageRotation struct {
+	PageNum  int           `json:"pageNum"`
+	Rotation RotationAngle `json:"rotation"`
+}
+
+// RotateResult holds the result of a rotate operation
+type RotateResult struct {
+	Success      bool   `json:"success"`
+	PagesRotated int    `json:"pagesRotated"`
+	OutputSize   int64  `json:"outputSize"`
+	OutputPath   string `json:"outputPath"`
+	Error        string `json:"error,omitempty"`
+}
+
+// OrientationSuggestion is the rotation that makes a page's text upright, Rotate0 if it already is.
+// It has the same JSON shape as PageRotation, so suggestions can be passed straight to ApplyRotations.
+type OrientationSuggestion struct {
+	PageNum    int           `json:"pageNum"`
+	Rotation   RotationAngle `json:"rotation"`
+	Confidence float64       `json:"confidence"` // 0-1, 0 when the page has too little text to judge
+}
+
+// PDFPermissions controls what someone opening an encrypted PDF with the user password may do
+type PDFPermissions struct {
+	Print bool `json:"print"`
+	Copy  bool `json:"copy"`
+	Edit  bool `json:"edit"`
+}
+
+// EncryptOptions configures password protection for an output PDF
+type EncryptOptions struct {
+	UserPassword  string         `json:"userPassword"`  // required to open the document
+	OwnerPassword string         `json:"ownerPassword"` // required to change permissions; random if empty
+	AES256        bool           `json:"aes256"`        // AES-256 instead of AES-128
+	Permissions   PDFPermissions `json:"permissions"`
+}
+
+// BlankPageOptions controls where InsertBlankPages adds pages, in addition to explicit positions
+type BlankPageOptions struct {
+	AfterEveryPage bool `json:"afterEveryPage"` // a blank page after each original page
+	PadToMultiple  int  `json:"padToMultiple"`  // append blank pages until the page count is a multiple of this (0 = off)
+}
+
+// PageSelectionResult is the outcome of validating a page selection expression for the UI
+type PageSelectionResult struct {
+	Valid  bool   `json:"valid"`
+	Pages  []int  `json:"pages,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Offset int    `json:"offset"` // character offset of the problem in the expression
+	Length int    `json:"length"` // number of characters to highlight
+}
+
 // MergeMode defines how to merge two PDFs
 type MergeMode string
 
 const (
-	MergeModeInterleave MergeMode = "interleave"
-	MergeModeAppend     MergeMode = "append"
+	MergeModeInterleave        MergeMode = "interleave"
+	MergeModeInterleaveReverse MergeMode = "interleave-reverse" // second file's pages in reverse, e.g. duplex scans
+	MergeModeAppend            MergeMode = "append"
 )
 
 // ProgressUpdate represents a progress event
@@ -2,13 +2,17 @@ package pdf
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -99,8 +103,8 @@ func ValidatePDF(path string) error {
 
 	if err := api.ValidateFile(path, nil); err != nil {
 		// Check if it's a password-protected PDF
-		if strings.Contains(err.Error(), "encrypted") || strings.Contains(err.Error(), "password") {
-			return fmt.Errorf("PDF is password-protected")
+		if errors.Is(err, pdfcpu.ErrWrongPassword) || strings.Contains(err.Error(), "encrypted") || strings.Contains(err.Error(), "password") {
+			return ErrPasswordProtected
 		}
 		return fmt.Errorf("invalid PDF: %w", err)
 	}
@@ -108,6 +112,41 @@ func ValidatePDF(path string) error {
 	return nil
 }
 
+// SanitizeFileName makes a title safe to use as a file name on all platforms
+func SanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < 32 || r == 127:
+			return ' '
+		case strings.ContainsRune(`<>:"/\|?*`, r):
+			return '_'
+		}
+		return r
+	}, name)
+
+	// Collapse whitespace and trim characters Windows rejects at the end
+	name = strings.Join(strings.Fields(name), " ")
+	name = strings.TrimRight(name, ". ")
+
+	if runes := []rune(name); len(runes) > 100 {
+		name = strings.TrimRight(string(runes[:100]), ". ")
+	}
+	return name
+}
+
+// readPDFContext parses a PDF into a pdfcpu context for in-memory operations
+func readPDFContext(path string, cmd model.CommandMode) (*model.Context, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	conf := model.NewDefaultConfiguration()
+	conf.Cmd = cmd
+	return api.ReadValidateAndOptimize(f, conf)
+}
+
 // CreateTempFile creates a temporary file with the given prefix and extension
 func CreateTempFile(prefix, ext string) (string, error) {
 	tmpDir := os.TempDir()
@@ -115,6 +154,24 @@ func CreateTempFile(prefix, ext string) (string, error) {
 	return tmpFile, nil
 }
 
+// CopyFile copies src to dst, replacing dst if it exists
+func CopyFile(src, dst string) error {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	_, err = io.Copy(destFile, sourceFile)
+	return err
+}
+
 // CleanupTempFiles removes temporary files created during processing
 func CleanupTempFiles(paths ...string) {
 	for _, path := range paths {
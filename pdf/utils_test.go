@@ -185,6 +185,31 @@ func TestValidatePDF_PasswordProtected(t *testing.T) {
 	}
 }
 
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Chapter 1", "Chapter 1"},
+		{"Q1/Q2: Results?", "Q1_Q2_ Results_"},
+		{"  lots   of\tspace  ", "lots of space"},
+		{"Ends with dots...", "Ends with dots"},
+		{"<>", "__"},
+		{"../../etc/passwd", ".._.._etc_passwd"},
+		{"..", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SanitizeFileName(tt.name)
+			if result != tt.expected {
+				t.Errorf("SanitizeFileName(%q) = %q, want %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestCreateTempFile(t *testing.T) {
 	path, err := CreateTempFile("test", ".pdf")
 	if err != nil {